@@ -5,20 +5,20 @@
 package assets
 
 import (
+	"image"
 	_ "image/png"
 	"log"
 	"path/filepath"
 	"strings"
 
 	"github.com/3elDU/bamboo/types"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// Textures are decoded into regular images, so they can be read without a graphics context.
+// Package textures uploads them to the GPU for the game.
 type AssetList struct {
-	Textures          map[string]*ebiten.Image
-	ConnectedTextures map[connectedTexture]*ebiten.Image
-
-	Font *ebiten.Image
+	Images          map[string]image.Image
+	ConnectedImages map[connectedTexture]image.Image
 }
 
 var (
@@ -31,14 +31,9 @@ func cleanPath(path string) string {
 	return strings.Replace(filepath.Base(path), filepath.Ext(path), "", 1)
 }
 
-func DefaultFont() *ebiten.Image {
-	return GlobalAssets.Font
-}
-
 // Texture panicks when a specified texture doesn't exist
 func Texture(name string) types.Texture {
-	_, exists := GlobalAssets.Textures[name]
-	if !exists {
+	if !TextureExists(name) {
 		log.Panicf("texture %v doesn't exist", name)
 	}
 	return &texture{
@@ -46,20 +41,41 @@ func Texture(name string) types.Texture {
 	}
 }
 
-// ConnectedTexture panicks when a specified texture doesn't exist
-func ConnectedTexture(baseName string, left, right, top, bottom bool) types.ConnectedTexture {
-	tex := connectedTexture{
-		baseName:       baseName,
-		connectedSides: [4]bool{left, right, top, bottom},
+// ConnectedTexture panicks when a specified texture atlas doesn't exist.
+// Which sides are connected is decided when the block is drawn.
+func ConnectedTexture(baseName string) types.Texture {
+	if !ConnectedTextureExists(baseName) {
+		log.Panicf("connected texture %v doesn't exist", baseName)
 	}
-	_, exists := GlobalAssets.ConnectedTextures[tex]
-	if !exists {
-		log.Panicf("connected texture %v doesn't exist", tex)
+	return &texture{
+		name: baseName,
 	}
-	return &tex
 }
 
-// Same as ConnectedTexture, but accepts an array of four booleans
-func ConnectedTextureFromArray(baseName string, sidesConnected [4]bool) types.ConnectedTexture {
-	return ConnectedTexture(baseName, sidesConnected[0], sidesConnected[1], sidesConnected[2], sidesConnected[3])
+// Returns true if the texture with given name exists
+func TextureExists(name string) bool {
+	_, exists := GlobalAssets.Images[name]
+	return exists
+}
+
+// Returns true if the connected texture atlas with given name exists
+func ConnectedTextureExists(baseName string) bool {
+	_, exists := ConnectedImage(baseName, [4]bool{})
+	return exists
+}
+
+// Image returns the texture as a regular image
+func Image(name string) (image.Image, bool) {
+	img, exists := GlobalAssets.Images[name]
+	return img, exists
+}
+
+// ConnectedImage returns the connected texture as a regular image.
+// Sides go in order: left, right, top, bottom
+func ConnectedImage(baseName string, sidesConnected [4]bool) (image.Image, bool) {
+	img, exists := GlobalAssets.ConnectedImages[connectedTexture{
+		baseName:       baseName,
+		connectedSides: sidesConnected,
+	}]
+	return img, exists
 }
@@ -0,0 +1,44 @@
+package assets
+
+import (
+	"embed"
+	"io/fs"
+	"log"
+	"path"
+	"strings"
+)
+
+// Blocks, items and recipes, that are described in JSON instead of Go code
+//
+//go:embed definitions
+var definitions embed.FS
+
+type DefinitionFile struct {
+	// Path of the file inside the definitions directory, for error messages
+	Path string
+	Data []byte
+}
+
+// Definitions returns the JSON files from definitions/<kind>, sorted by name
+func Definitions(kind string) []DefinitionFile {
+	dir := path.Join("definitions", kind)
+	entries, err := fs.ReadDir(definitions, dir)
+	if err != nil {
+		log.Panicf("failed to read %v definitions - %v", kind, err)
+	}
+
+	files := make([]DefinitionFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		filePath := path.Join(dir, entry.Name())
+		data, err := fs.ReadFile(definitions, filePath)
+		if err != nil {
+			log.Panicf("failed to read definition %v - %v", filePath, err)
+		}
+		files = append(files, DefinitionFile{Path: filePath, Data: data})
+	}
+	return files
+}
@@ -7,8 +7,6 @@ import (
 	"io/fs"
 	"log"
 	"path/filepath"
-
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 //go:embed assets/*
@@ -28,7 +26,7 @@ func parseTexture(assetList *AssetList, path string) error {
 	if err != nil {
 		return err
 	}
-	assetList.Textures[cleanPath(path)] = ebiten.NewImageFromImage(img)
+	assetList.Images[cleanPath(path)] = img
 
 	return nil
 }
@@ -45,7 +43,10 @@ func parseConnectedTexture(assetList *AssetList, path string) error {
 		return err
 	}
 
-	tex := ebiten.NewImageFromImage(img)
+	// all decoded PNG images support SubImage()
+	subImager := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
 
 	// texture map describes, which sub-texture is on which coordinate
 	// first and second indices represent coordinates ( multiples of 16 ) on an atlas
@@ -60,19 +61,17 @@ func parseConnectedTexture(assetList *AssetList, path string) error {
 
 	for y, row := range textureMap {
 		for x, col := range row {
-			assetList.ConnectedTextures[connectedTexture{
+			key := connectedTexture{
 				baseName:       cleanPath(path),
 				connectedSides: col,
-			}] = ebiten.NewImageFromImage(
-				tex.SubImage(image.Rect(x*16, y*16, x*16+16, y*16+16)),
-			)
+			}
+			rect := image.Rect(x*16, y*16, x*16+16, y*16+16)
+			assetList.ConnectedImages[key] = subImager.SubImage(rect)
 		}
 	}
 
 	// also save a texture with no connected sides, as a regular texture
-	assetList.Textures[cleanPath(path)] = ebiten.NewImageFromImage(tex.SubImage(
-		image.Rect(0, 0, 16, 16),
-	))
+	assetList.Images[cleanPath(path)] = subImager.SubImage(image.Rect(0, 0, 16, 16))
 
 	return nil
 }
@@ -80,8 +79,8 @@ func parseConnectedTexture(assetList *AssetList, path string) error {
 // LoadAssets walks the assets directory and loads all the assets
 func LoadAssets() {
 	assetList := &AssetList{
-		Textures:          make(map[string]*ebiten.Image),
-		ConnectedTextures: make(map[connectedTexture]*ebiten.Image),
+		Images:          make(map[string]image.Image),
+		ConnectedImages: make(map[connectedTexture]image.Image),
 	}
 
 	err := fs.WalkDir(assets, "assets", func(path string, d fs.DirEntry, err error) error {
@@ -105,11 +104,9 @@ func LoadAssets() {
 		log.Panicln(err)
 	}
 
-	font, exists := assetList.Textures["font"]
-	if !exists {
+	if _, exists := assetList.Images["font"]; !exists {
 		log.Panicln("cannot find the font texture")
 	}
-	assetList.Font = font
 
 	GlobalAssets = assetList
 }
@@ -55,8 +55,9 @@ func (b *baseBlock) Type() types.BlockType {
 	return b.blockType
 }
 
-func (b *baseBlock) Update(_ types.World) {
-
+// Asks the parent chunk to tick the block after delay ticks
+func (b *baseBlock) scheduleTick(delay uint64) {
+	b.parentChunk.ScheduleTick(b.x%16, b.y%16, delay)
 }
 
 func (b *baseBlock) State() interface{} {
@@ -66,6 +67,7 @@ func (b *baseBlock) State() interface{} {
 }
 
 func (b *baseBlock) LoadState(s interface{}) {
-	state := s.(BaseBlockState)
-	b.blockType = state.BlockType
+	// The block type is set by the constructor.
+	// Type saved in the state is a numeric ID, which may belong to a different block by now
+	_ = s.(BaseBlockState)
 }
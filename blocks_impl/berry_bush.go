@@ -1,11 +1,11 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
 	"fmt"
 	"log"
 	"math/rand"
 
+	"github.com/3elDU/bamboo/clock"
 	"github.com/3elDU/bamboo/types"
 
 	"github.com/3elDU/bamboo/assets"
@@ -18,7 +18,12 @@ const BerryGrowthTime = 60 * 60 * 5
 const MaxBerriesGrown = 10
 
 func init() {
-	gob.Register(BerryBushState{})
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.BerryBushBlock,
+		Key:   "bamboo:berry_bush",
+		New:   func() types.Block { return NewBerryBushBlock(0) },
+		State: BerryBushState{},
+	})
 	types.NewBerryBushBlock = NewBerryBushBlock
 }
 
@@ -39,10 +44,11 @@ type BerryBushBlock struct {
 	// To be able to grow berries again, bush needs watering, which can be done with the funnel.
 	// Then, the process repeats.
 	// This can be avoided by growing a bush directly near a water source.
-	driedOut           bool
-	berries            int
-	totalBerriesGrown  int
-	ticksTillNextBerry int
+	driedOut          bool
+	berries           int
+	totalBerriesGrown int
+	// Value of clock.Ticks(), when the next berry grows
+	nextBerryAt uint64
 }
 
 func NewBerryBushBlock(berries int) types.Block {
@@ -53,10 +59,10 @@ func NewBerryBushBlock(berries int) types.Block {
 		texturedBlock: texturedBlock{
 			tex: assets.Texture(fmt.Sprintf("bush%v", berries)),
 		},
-		driedOut:           false,
-		berries:            berries,
-		totalBerriesGrown:  berries,
-		ticksTillNextBerry: rand.Intn(BerryGrowthTime),
+		driedOut:          false,
+		berries:           berries,
+		totalBerriesGrown: berries,
+		nextBerryAt:       clock.Ticks() + uint64(rand.Intn(BerryGrowthTime)),
 	}
 }
 
@@ -67,8 +73,8 @@ func (b *BerryBushBlock) NeedsWatering() bool {
 func (b *BerryBushBlock) AddWater() {
 	b.driedOut = false
 	b.totalBerriesGrown = 0
-	b.ticksTillNextBerry = rand.Intn(BerryGrowthTime)
 	b.setBerries(0)
+	b.growNextBerry()
 	b.parentChunk.MarkAsModified()
 }
 
@@ -77,39 +83,64 @@ func (b *BerryBushBlock) setBerries(berries int) {
 	if b.berries > 4 {
 		b.berries = 4
 	}
-	b.texturedBlock = texturedBlock{
-		tex: assets.Texture(fmt.Sprintf("bush%v", b.berries)),
+	b.updateTexture()
+}
+
+// Dried out bush keeps its berries, until they are picked
+func (b *BerryBushBlock) updateTexture() {
+	if b.driedOut && b.berries == 0 {
+		b.tex = assets.Texture("dried_out_bush")
+	} else {
+		b.tex = assets.Texture(fmt.Sprintf("bush%v", b.berries))
 	}
 }
 
-func (b *BerryBushBlock) Update(world types.World) {
-	if b.berries < 4 && b.ticksTillNextBerry <= 0 && !b.driedOut {
-		b.setBerries(b.berries + 1)
-		b.totalBerriesGrown += 1
-		log.Printf("Total berries grown: %v", b.totalBerriesGrown)
+func (b *BerryBushBlock) canGrow() bool {
+	return b.berries < 4 && !b.driedOut
+}
 
-		if b.berries < 4 {
-			b.ticksTillNextBerry = rand.Intn(BerryGrowthTime)
-		}
+func (b *BerryBushBlock) ticksTillNextBerry() uint64 {
+	now := clock.Ticks()
+	if b.nextBerryAt <= now {
+		return 0
+	}
+	return b.nextBerryAt - now
+}
 
-		b.parentChunk.MarkAsModified()
+// Picks the time for the next berry, and asks the chunk to tick the bush then
+func (b *BerryBushBlock) growNextBerry() {
+	b.nextBerryAt = clock.Ticks() + uint64(rand.Intn(BerryGrowthTime))
+	b.scheduleTick(b.ticksTillNextBerry())
+}
+
+func (b *BerryBushBlock) InitialTickDelay() uint64 {
+	if !b.canGrow() {
+		return 0
+	}
+	// the berry is already overdue, grow it on the next tick
+	if b.ticksTillNextBerry() == 0 {
+		return 1
 	}
+	return b.ticksTillNextBerry()
+}
+
+func (b *BerryBushBlock) ScheduledTick(world types.World) {
+	if !b.canGrow() {
+		return
+	}
+
+	b.setBerries(b.berries + 1)
+	b.totalBerriesGrown += 1
+	log.Printf("Total berries grown: %v", b.totalBerriesGrown)
 
 	// Bush can grow a fixed amount of berries, after which it dries out
-	if b.totalBerriesGrown >= MaxBerriesGrown && !b.driedOut {
+	if b.totalBerriesGrown >= MaxBerriesGrown {
 		b.driedOut = true
-		b.parentChunk.MarkAsModified()
-	}
-	// Change the texture to dried out bush, when there are no more berries left
-	if b.driedOut && b.berries == 0 {
-		// Do not change the texture if it was already changed
-		if b.tex.Name() != "dried_out_bush" {
-			b.tex = assets.Texture("dried_out_bush")
-			b.parentChunk.MarkAsModified()
-		}
+	} else if b.berries < 4 {
+		b.growNextBerry()
 	}
 
-	b.ticksTillNextBerry -= 1
+	b.parentChunk.MarkAsModified()
 }
 
 func (b *BerryBushBlock) ToolRequiredToBreak() types.ToolFamily {
@@ -124,10 +155,17 @@ func (b *BerryBushBlock) Break() {
 	}
 
 	if types.GetPlayerInventory().AddItem(types.ItemSlot{
-		Item:     types.NewBerryItem(),
+		Item:     types.NewItem(types.BerryItem),
 		Quantity: 1,
 	}) {
+		wasFull := !b.canGrow()
 		b.setBerries(b.berries - 1)
+
+		// The bush stops growing berries when it is full, so it has to be scheduled again
+		if wasFull && b.canGrow() {
+			b.growNextBerry()
+		}
+
 		b.parentChunk.MarkAsModified()
 	}
 }
@@ -138,7 +176,7 @@ func (b *BerryBushBlock) State() interface{} {
 		DriedOut:           b.driedOut,
 		Berries:            b.berries,
 		TotalBerriesGrown:  b.totalBerriesGrown,
-		TicksTillNextBerry: b.ticksTillNextBerry,
+		TicksTillNextBerry: int(b.ticksTillNextBerry()),
 	}
 }
 
@@ -147,11 +185,12 @@ func (b *BerryBushBlock) LoadState(s interface{}) {
 	b.baseBlock.LoadState(state.BaseBlockState)
 
 	b.driedOut = state.DriedOut
-	if b.driedOut {
-		b.tex = assets.Texture("dried_out_bush")
-	}
-
 	b.setBerries(state.Berries)
 	b.totalBerriesGrown = state.TotalBerriesGrown
-	b.ticksTillNextBerry = state.TicksTillNextBerry
+	// older saves kept counting down, after the bush was full
+	ticksTillNextBerry := state.TicksTillNextBerry
+	if ticksTillNextBerry < 0 {
+		ticksTillNextBerry = 0
+	}
+	b.nextBerryAt = clock.Ticks() + uint64(ticksTillNextBerry)
 }
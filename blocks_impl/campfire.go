@@ -1,7 +1,6 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
 	"fmt"
 
 	"github.com/3elDU/bamboo/assets"
@@ -9,8 +8,12 @@ import (
 )
 
 func init() {
-	gob.Register(CampfireBlockState{})
-	types.NewCampfireBlock = NewCampfireBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.CampfireBlock,
+		Key:   "bamboo:campfire",
+		New:   NewCampfireBlock,
+		State: CampfireBlockState{},
+	})
 }
 
 type CampfireBlockState struct {
@@ -81,11 +84,11 @@ func (campfire *CampfireBlock) ToolStrengthRequired() types.ToolStrength {
 }
 func (campfire *CampfireBlock) Break() {
 	added := types.GetPlayerInventory().AddItem(types.ItemSlot{
-		Item:     types.NewStickItem(),
+		Item:     types.NewItem(types.StickItem),
 		Quantity: uint8(campfire.pieces),
 	})
 	if added {
-		types.GetCurrentWorld().SetBlock(uint64(campfire.x), uint64(campfire.y), types.NewGrassBlock())
+		types.GetCurrentWorld().SetBlock(uint64(campfire.x), uint64(campfire.y), types.NewBlock(types.GrassBlock))
 	}
 }
 
@@ -1,16 +1,20 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
-
 	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/event"
 	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/world_type"
 	"github.com/google/uuid"
 )
 
 func init() {
-	gob.Register(CaveEntranceState{})
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.CaveEntranceBlock,
+		Key:   "bamboo:cave_entrance",
+		New:   func() types.Block { return NewCaveEntranceBlock(uuid.New()) },
+		State: CaveEntranceState{},
+	})
 	types.NewCaveEntranceBlock = NewCaveEntranceBlock
 }
 
@@ -58,7 +62,8 @@ func (cave *CaveEntranceBlock) Collide(_ types.World, _ types.Vec2f) {
 	event.FireEvent(event.NewEvent(
 		event.CaveEnter,
 		event.CaveEnteredArgs{
-			ID: cave.id,
+			ID:        cave.id,
+			WorldType: world_type.Cave,
 		},
 	))
 }
@@ -0,0 +1,75 @@
+package blocks_impl
+
+import (
+	"github.com/3elDU/bamboo/assets"
+	"github.com/3elDU/bamboo/event"
+	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/world_type"
+	"github.com/google/uuid"
+)
+
+func init() {
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.CaveDescentBlock,
+		Key:   "bamboo:cave_descent",
+		New:   func() types.Block { return NewCaveDescentBlock(uuid.New(), world_type.Cave2) },
+		State: CaveDescentState{},
+	})
+	types.NewCaveDescentBlock = NewCaveDescentBlock
+}
+
+type CaveDescentState struct {
+	BaseBlockState
+	TexturedBlockState
+	ID     uuid.UUID
+	Target world_type.WorldType
+}
+
+// Leads from a cave to a deeper cave tier
+type CaveDescentBlock struct {
+	baseBlock
+	texturedBlock
+
+	id     uuid.UUID
+	target world_type.WorldType
+}
+
+func NewCaveDescentBlock(id uuid.UUID, target world_type.WorldType) types.Block {
+	return &CaveDescentBlock{
+		baseBlock: baseBlock{
+			blockType: types.CaveDescentBlock,
+		},
+		texturedBlock: texturedBlock{
+			tex: assets.Texture("cave_descent"),
+		},
+		id:     id,
+		target: target,
+	}
+}
+
+func (descent *CaveDescentBlock) State() interface{} {
+	return CaveDescentState{
+		BaseBlockState:     descent.baseBlock.State().(BaseBlockState),
+		TexturedBlockState: descent.texturedBlock.State().(TexturedBlockState),
+		ID:                 descent.id,
+		Target:             descent.target,
+	}
+}
+
+func (descent *CaveDescentBlock) LoadState(s interface{}) {
+	state := s.(CaveDescentState)
+	descent.baseBlock.LoadState(state.BaseBlockState)
+	descent.texturedBlock.LoadState(state.TexturedBlockState)
+	descent.id = state.ID
+	descent.target = state.Target
+}
+
+func (descent *CaveDescentBlock) Collide(_ types.World, _ types.Vec2f) {
+	event.FireEvent(event.NewEvent(
+		event.CaveEnter,
+		event.CaveEnteredArgs{
+			ID:        descent.id,
+			WorldType: descent.target,
+		},
+	))
+}
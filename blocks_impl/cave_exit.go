@@ -1,16 +1,29 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
-
 	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/event"
 	"github.com/3elDU/bamboo/types"
 )
 
 func init() {
-	gob.Register(CaveExitState{})
-	types.NewCaveExitBlock = NewCaveExitBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.CaveExitBlock,
+		Key:   "bamboo:cave_exit",
+		New:   NewCaveExitBlock,
+		State: CaveExitState{},
+	})
+
+	// Before save format version 1, cave exit was saved with CaveEntranceState
+	types.RegisterBlockMigration(1, types.CaveExitBlock, func(s interface{}) interface{} {
+		if state, ok := s.(CaveEntranceState); ok {
+			return CaveExitState{
+				BaseBlockState:     state.BaseBlockState,
+				TexturedBlockState: state.TexturedBlockState,
+			}
+		}
+		return s
+	})
 }
 
 type CaveExitState struct {
@@ -35,14 +48,14 @@ func NewCaveExitBlock() types.Block {
 }
 
 func (cave *CaveExitBlock) State() interface{} {
-	return CaveEntranceState{
+	return CaveExitState{
 		BaseBlockState:     cave.baseBlock.State().(BaseBlockState),
 		TexturedBlockState: cave.texturedBlock.State().(TexturedBlockState),
 	}
 }
 
 func (cave *CaveExitBlock) LoadState(s interface{}) {
-	state := s.(CaveEntranceState)
+	state := s.(CaveExitState)
 	cave.baseBlock.LoadState(state.BaseBlockState)
 	cave.texturedBlock.LoadState(state.TexturedBlockState)
 }
@@ -6,6 +6,11 @@ import (
 )
 
 func init() {
+	types.RegisterBlock(types.BlockDefinition{
+		Type: types.CaveFloorBlock,
+		Key:  "bamboo:cave_floor",
+		New:  func() types.Block { return NewCaveFloorBlock(false) },
+	})
 	types.NewCaveFloorBlock = NewCaveFloorBlock
 }
 
@@ -7,7 +7,11 @@ import (
 )
 
 func init() {
-	types.NewCaveWallBlock = NewCaveWallBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type: types.CaveWallBlock,
+		Key:  "bamboo:cave_wall",
+		New:  NewCaveWallBlock,
+	})
 }
 
 type CaveWallBlock struct {
@@ -21,7 +25,7 @@ func NewCaveWallBlock() types.Block {
 			baseBlock: baseBlock{
 				blockType: types.CaveWallBlock,
 			},
-			tex:        assets.ConnectedTexture("cave_wall", false, false, false, false),
+			tex:        assets.ConnectedTexture("cave_wall"),
 			connectsTo: []types.BlockType{types.CaveWallBlock},
 		},
 		collidableBlock: collidableBlock{
@@ -6,7 +6,6 @@ import (
 
 	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
-	"github.com/hajimehoshi/ebiten/v2"
 	"golang.org/x/exp/slices"
 )
 
@@ -23,40 +22,22 @@ type ConnectedBlockState struct {
 type connectedBlock struct {
 	baseBlock
 	connectsTo     []types.BlockType
-	tex            types.ConnectedTexture
+	tex            types.Texture
 	sidesConnected [4]bool
 }
 
-func (b *connectedBlock) shouldConnect(other types.BlockType) bool {
+func (b *connectedBlock) ShouldConnect(other types.BlockType) bool {
 	return slices.Contains(b.connectsTo, other)
 }
 
-func (b *connectedBlock) Render(world types.World, screen *ebiten.Image, pos types.Vec2f, recursiveRedraw bool) {
-	var connectedSides [4]bool
-	for i, side := range [4]types.Vec2i{{X: -1, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: 0, Y: 1}} {
-		x, y := int(b.x)+side.X, int(b.y)+side.Y
-		neighbor := world.BlockAt(uint64(x), uint64(y))
-		if !b.shouldConnect(neighbor.Type()) {
-			continue
-		}
-
-		connectedSides[i] = true
-		// If neighbor is on another chunk, trigger a non-recursive redraw of that chunk
-		if recursiveRedraw && neighbor.ParentChunk() != b.parentChunk {
-			neighbor.ParentChunk().TriggerRedraw(false)
-		}
-	}
-	b.tex.SetConnectedSides(connectedSides)
-
-	opts := &ebiten.DrawImageOptions{}
-	opts.GeoM.Translate(pos.X, pos.Y)
-	screen.DrawImage(b.tex.Texture(), opts)
-}
-
 func (b *connectedBlock) TextureName() string {
 	return b.tex.Name()
 }
 
+func (b *connectedBlock) TextureRotation() float64 {
+	return 0
+}
+
 func (b *connectedBlock) State() interface{} {
 	return ConnectedBlockState{
 		BaseBlockState: b.baseBlock.State().(BaseBlockState),
@@ -72,5 +53,5 @@ func (b *connectedBlock) LoadState(s interface{}) {
 	}
 
 	b.baseBlock.LoadState(state.BaseBlockState)
-	b.tex = assets.ConnectedTextureFromArray(state.Texture, state.SidesConnected)
+	b.tex = assets.ConnectedTexture(state.Texture)
 }
@@ -0,0 +1,240 @@
+/*
+	Blocks described in JSON files in assets/definitions/blocks.
+	Simple blocks, that only have a texture, collision, connect to their neighbors,
+	and drop items when broken, don't need any Go code.
+*/
+
+package blocks_impl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/3elDU/bamboo/assets"
+	"github.com/3elDU/bamboo/types"
+	"golang.org/x/exp/slices"
+)
+
+func init() {
+	for _, file := range assets.Definitions("blocks") {
+		data, err := parseBlockData(file.Data)
+		if err != nil {
+			log.Panicf("invalid block definition %v - %v", file.Path, err)
+		}
+		data.register()
+	}
+}
+
+type blockDrop struct {
+	Item   string `json:"item"`
+	Amount uint8  `json:"amount"`
+}
+
+// Block definition, as it is written in the JSON file
+type blockData struct {
+	Key string `json:"key"`
+	// Name of the type in types.BlockType enum.
+	// Only needed for blocks, that Go code refers to, others get their ID automatically.
+	Type string `json:"type"`
+
+	Texture     string  `json:"texture"`
+	Collidable  bool    `json:"collidable"`
+	PlayerSpeed float64 `json:"player_speed"`
+	// Keys of the blocks, that this block visually connects to.
+	// If it is set, texture is the name of a connected texture atlas.
+	ConnectsTo []string `json:"connects_to"`
+
+	// The block is breakable, if it has a block to be replaced with
+	Tool         types.ToolFamily   `json:"tool"`
+	ToolStrength types.ToolStrength `json:"tool_strength"`
+	Drops        []blockDrop        `json:"drops"`
+	BrokenInto   string             `json:"broken_into"`
+
+	blockType types.BlockType
+	// resolved from ConnectsTo on first use, when all blocks are registered
+	connectsTo        []types.BlockType
+	resolveConnection sync.Once
+}
+
+func parseBlockData(file []byte) (*blockData, error) {
+	data := &blockData{
+		PlayerSpeed: 1,
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(file))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(data); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case data.Key == "":
+		return nil, fmt.Errorf("block has no key")
+	case len(data.ConnectsTo) == 0 && !assets.TextureExists(data.Texture):
+		return nil, fmt.Errorf("texture %q doesn't exist", data.Texture)
+	case len(data.ConnectsTo) > 0 && !assets.ConnectedTextureExists(data.Texture):
+		return nil, fmt.Errorf("connected texture %q doesn't exist", data.Texture)
+	case data.PlayerSpeed <= 0:
+		return nil, fmt.Errorf("player speed must be positive")
+	case len(data.Drops) > 0 && data.BrokenInto == "":
+		return nil, fmt.Errorf("block has drops, but can't be broken")
+	}
+	for _, drop := range data.Drops {
+		if drop.Amount == 0 {
+			return nil, fmt.Errorf("drop of %q has no amount", drop.Item)
+		}
+	}
+
+	if data.Type == "" {
+		data.blockType = types.AllocateBlockType()
+	} else {
+		blockType, exists := types.BlockTypeByName(data.Type)
+		if !exists {
+			return nil, fmt.Errorf("unknown block type %q", data.Type)
+		}
+		data.blockType = blockType
+	}
+	return data, nil
+}
+
+func (data *blockData) register() {
+	types.RegisterBlock(types.BlockDefinition{
+		Type: data.blockType,
+		Key:  data.Key,
+		New:  data.newBlock,
+	})
+
+	// drops and the replacement block may be registered after this block
+	types.AddRegistryCheck(func() error {
+		if data.BrokenInto != "" {
+			if _, exists := types.BlockTypeByKey(data.BrokenInto); !exists {
+				return fmt.Errorf("block %q breaks into unknown block %q", data.Key, data.BrokenInto)
+			}
+		}
+		for _, drop := range data.Drops {
+			if _, exists := types.ItemTypeByKey(drop.Item); !exists {
+				return fmt.Errorf("block %q drops unknown item %q", data.Key, drop.Item)
+			}
+		}
+		for _, key := range data.ConnectsTo {
+			if _, exists := types.BlockTypeByKey(key); !exists {
+				return fmt.Errorf("block %q connects to unknown block %q", data.Key, key)
+			}
+		}
+		return nil
+	})
+}
+
+func (data *blockData) shouldConnect(other types.BlockType) bool {
+	data.resolveConnection.Do(func() {
+		for _, key := range data.ConnectsTo {
+			blockType, exists := types.BlockTypeByKey(key)
+			if !exists {
+				log.Panicf("block %q connects to unknown block %q", data.Key, key)
+			}
+			data.connectsTo = append(data.connectsTo, blockType)
+		}
+	})
+	return slices.Contains(data.connectsTo, other)
+}
+
+// Only connected blocks implement types.ConnectedBlock, because the renderer checks for it with a type assertion
+func (data *blockData) newBlock() types.Block {
+	connected := len(data.ConnectsTo) > 0
+	tex := assets.Texture
+	if connected {
+		tex = assets.ConnectedTexture
+	}
+
+	block := dataBlock{
+		baseBlock: baseBlock{
+			blockType: data.blockType,
+		},
+		texturedBlock: texturedBlock{
+			tex: tex(data.Texture),
+		},
+		collidableBlock: collidableBlock{
+			collidable:  data.Collidable,
+			playerSpeed: data.PlayerSpeed,
+		},
+		data: data,
+	}
+	if data.Collidable {
+		block.collisionPoints = defaultCollisionPoints()
+	}
+
+	if data.BrokenInto == "" {
+		if connected {
+			return &connectedDataBlock{dataBlock: block}
+		}
+		return &block
+	}
+
+	breakable := breakableDataBlock{
+		dataBlock: block,
+		breakableBlock: breakableBlock{
+			toolRequiredToBreak:  data.Tool,
+			toolStrengthRequired: data.ToolStrength,
+		},
+	}
+	if connected {
+		return &breakableConnectedDataBlock{breakableDataBlock: breakable}
+	}
+	return &breakable
+}
+
+type dataBlock struct {
+	baseBlock
+	texturedBlock
+	collidableBlock
+
+	data *blockData
+}
+
+// Everything about the block comes from the definition, so there is no state to save
+func (block *dataBlock) State() interface{} {
+	return nil
+}
+func (block *dataBlock) LoadState(_ interface{}) {
+
+}
+
+type breakableDataBlock struct {
+	dataBlock
+	breakableBlock
+}
+
+func (block *breakableDataBlock) Break() {
+	drops := make([]types.ItemSlot, 0, len(block.data.Drops))
+	for _, drop := range block.data.Drops {
+		item, _ := types.NewItemByKey(drop.Item)
+		drops = append(drops, types.NewItemSlot(item, drop.Amount))
+	}
+
+	// the block isn't broken, if the drops don't fit into the inventory
+	if !types.GetPlayerInventory().AddItems(drops...) {
+		return
+	}
+
+	replacement, _ := types.NewBlockByKey(block.data.BrokenInto)
+	types.GetCurrentWorld().SetBlock(uint64(block.x), uint64(block.y), replacement)
+}
+
+type connectedDataBlock struct {
+	dataBlock
+}
+
+func (block *connectedDataBlock) ShouldConnect(other types.BlockType) bool {
+	return block.data.shouldConnect(other)
+}
+
+type breakableConnectedDataBlock struct {
+	breakableDataBlock
+}
+
+func (block *breakableConnectedDataBlock) ShouldConnect(other types.BlockType) bool {
+	return block.data.shouldConnect(other)
+}
@@ -9,17 +9,17 @@ import (
 )
 
 func init() {
-	types.NewEmptyBlock = NewEmptyBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type: types.EmptyBlock,
+		Key:  "bamboo:empty",
+		New:  NewEmptyBlock,
+	})
 }
 
 type EmptyBlock struct {
 	baseBlock
 }
 
-func (e *EmptyBlock) Update(_ types.World) {
-
-}
-
 func NewEmptyBlock() types.Block {
 	return &EmptyBlock{
 		baseBlock: baseBlock{
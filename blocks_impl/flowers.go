@@ -1,16 +1,18 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
-
 	"github.com/3elDU/bamboo/types"
 
 	"github.com/3elDU/bamboo/assets"
 )
 
 func init() {
-	gob.Register(FlowersState{})
-	types.NewFlowersBlock = NewFlowersBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.FlowersBlock,
+		Key:   "bamboo:flowers",
+		New:   NewFlowersBlock,
+		State: FlowersState{},
+	})
 }
 
 type FlowersState struct {
@@ -42,7 +44,7 @@ func (b *FlowersBlock) ToolStrengthRequired() types.ToolStrength {
 	return types.ToolStrengthBareHand
 }
 func (b *FlowersBlock) Break() {
-	types.GetCurrentWorld().SetBlock(uint64(b.x), uint64(b.y), types.NewGrassBlock())
+	types.GetCurrentWorld().SetBlock(uint64(b.x), uint64(b.y), types.NewBlock(types.GrassBlock))
 }
 
 func (b *FlowersBlock) State() interface{} {
@@ -1,23 +1,21 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
-
 	"github.com/3elDU/bamboo/assets"
-	"github.com/3elDU/bamboo/colors"
-	"github.com/3elDU/bamboo/scene_manager"
+	"github.com/3elDU/bamboo/event"
 	"github.com/3elDU/bamboo/types"
-	"github.com/3elDU/bamboo/ui"
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 // After an item has been smelted, wait for 5s until smelting another item
 const FurnaceSmeltingCooldown = 300
 
 func init() {
-	gob.Register(FurnaceBlockState{})
-	types.NewFurnaceBlock = NewFurnaceBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.FurnaceBlock,
+		Key:   "bamboo:furnace",
+		New:   NewFurnaceBlock,
+		State: FurnaceBlockState{},
+	})
 }
 
 type FurnaceBlockState struct {
@@ -43,6 +41,11 @@ func (furnace *FurnaceBlock) isSmelting() bool {
 	return !furnace.inputInventory.Empty && furnace.energy >= furnace.inputInventory.Item.(types.ISmeltableItem).SmeltingEnergyRequired()
 }
 
+// The furnace is lit, while it is smelting something
+func (furnace *FurnaceBlock) IsLitUp() bool {
+	return furnace.isSmelting()
+}
+
 func (furnace *FurnaceBlock) updateTexture() {
 	if furnace.isSmelting() {
 		furnace.tex = assets.Texture("furnace_burning")
@@ -66,6 +69,24 @@ func NewFurnaceBlock() types.Block {
 	return furnace
 }
 
+func (furnace *FurnaceBlock) Energy() float64 {
+	return furnace.energy
+}
+func (furnace *FurnaceBlock) InputSlot() *types.ItemSlot {
+	return &furnace.inputInventory
+}
+func (furnace *FurnaceBlock) OutputSlot() *types.ItemSlot {
+	return &furnace.outputInventory
+}
+
+func (furnace *FurnaceBlock) AddItem(item types.Item) bool {
+	if burnableItem, burnable := item.(types.IBurnableItem); burnable {
+		furnace.energy += burnableItem.BurningEnergy()
+		return true
+	}
+	return furnace.smeltItem(item)
+}
+
 func (furnace *FurnaceBlock) smeltItem(item types.Item) bool {
 	// Accept only smeltable items
 	_, isSmeltable := item.(types.ISmeltableItem)
@@ -113,7 +134,8 @@ func (furnace *FurnaceBlock) Update(_ types.World) {
 }
 
 func (furnace *FurnaceBlock) Interact() {
-	scene_manager.ShowOverlay(&FurnaceScene{furnace})
+	// the game shows the furnace screen
+	event.FireEvent(event.NewEvent(event.FurnaceOpen, furnace))
 }
 
 func (furnace *FurnaceBlock) ToolRequiredToBreak() types.ToolFamily {
@@ -123,7 +145,7 @@ func (furnace *FurnaceBlock) ToolStrengthRequired() types.ToolStrength {
 	return types.ToolStrengthWood
 }
 func (furnace *FurnaceBlock) Break() {
-	furnace.parentChunk.SetBlock(furnace.x, furnace.y, types.NewGrassBlock())
+	furnace.parentChunk.SetBlock(furnace.x, furnace.y, types.NewBlock(types.GrassBlock))
 }
 
 func (furnace *FurnaceBlock) State() interface{} {
@@ -142,62 +164,3 @@ func (furnace *FurnaceBlock) LoadState(state interface{}) {
 		furnace.smeltingCooldown = furnaceState.SmeltingCooldown
 	}
 }
-
-type FurnaceScene struct {
-	*FurnaceBlock
-}
-
-func (scene *FurnaceScene) Update() {
-	// If a player is more than 3 blocks away from the furnace, close the interface
-	if scene.FurnaceBlock.Coords().DistanceTo(types.GetCurrentPlayer().Position()) > 3 {
-		scene_manager.HideOverlay()
-	}
-
-	switch {
-	case inpututil.IsKeyJustPressed(ebiten.KeyP):
-		if types.GetPlayerInventory().SelectedSlot().Empty {
-			break
-		}
-
-		itemInHand := types.GetPlayerInventory().ItemInHand()
-		if burnableItem, burnable := itemInHand.(types.IBurnableItem); burnable {
-			scene.FurnaceBlock.energy += burnableItem.BurningEnergy()
-			types.GetPlayerInventory().SelectedSlot().RemoveItem(1)
-		} else if scene.FurnaceBlock.smeltItem(itemInHand) {
-			types.GetPlayerInventory().SelectedSlot().RemoveItem(1)
-		}
-	case inpututil.IsKeyJustPressed(ebiten.KeyT):
-		if types.GetPlayerInventory().AddItem(scene.FurnaceBlock.outputInventory) {
-			scene.FurnaceBlock.outputInventory = types.ItemSlot{Empty: true}
-		}
-	}
-}
-
-func (scene *FurnaceScene) Draw(screen *ebiten.Image) {
-	ui.ImmediateDraw(screen, ui.Styled(ui.Padding(1.0,
-		ui.VStack().WithSpacing(2.0).WithChildren(
-			ui.Background(colors.C("blue"), ui.PaddingXY(1.0, 0.3,
-				ui.CustomLabel("Furnace", colors.C("white"), 1.5),
-			)),
-
-			ui.Tooltip(ui.VStack().WithSpacing(1.5).WithChildren(
-				ui.VStack(
-					ui.Label("Fuel"),
-					ui.LabelF("%v", scene.FurnaceBlock.energy),
-				),
-				ui.VStack(
-					ui.Label("Items to smelt"),
-					ui.ItemSlot(&scene.FurnaceBlock.inputInventory),
-				),
-				ui.VStack(
-					ui.Label("Smelted items"),
-					ui.ItemSlot(&scene.FurnaceBlock.outputInventory),
-				),
-			)),
-		),
-	)).WithTextColor(colors.C("white")))
-}
-
-func (scene *FurnaceScene) Destroy() {
-
-}
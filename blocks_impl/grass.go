@@ -1,15 +1,17 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
-
 	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
 )
 
 func init() {
-	gob.Register(GrassBlockState{})
-	types.NewGrassBlock = NewGrassBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.GrassBlock,
+		Key:   "bamboo:grass",
+		New:   NewGrassBlock,
+		State: GrassBlockState{},
+	})
 }
 
 type GrassBlockState struct {
@@ -26,7 +28,7 @@ func NewGrassBlock() types.Block {
 			baseBlock: baseBlock{
 				blockType: types.GrassBlock,
 			},
-			tex: assets.ConnectedTexture("grass", true, true, true, true),
+			tex: assets.ConnectedTexture("grass"),
 			connectsTo: []types.BlockType{
 				types.GrassBlock, types.ShortGrassBlock, types.TallGrassBlock, types.FlowersBlock, types.PineSaplingBlock, types.BerryBushBlock,
 				types.PineTreeBlock,
@@ -47,7 +49,7 @@ func (b *GrassBlock) ToolStrengthRequired() types.ToolStrength {
 	return types.ToolStrengthGold
 }
 func (b *GrassBlock) Break() {
-	types.GetCurrentWorld().SetBlock(uint64(b.x), uint64(b.y), types.NewPitBlock())
+	types.GetCurrentWorld().SetBlock(uint64(b.x), uint64(b.y), types.NewBlock(types.PitBlock))
 }
 
 func (b *GrassBlock) State() interface{} {
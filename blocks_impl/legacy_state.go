@@ -0,0 +1,26 @@
+package blocks_impl
+
+import "encoding/gob"
+
+// States of the blocks, that were moved to assets/definitions.
+// Chunks saved before that still contain them, and gob can't decode a chunk
+// with an unregistered type, so they are kept, even though data blocks ignore their state.
+func init() {
+	gob.Register(StoneState{})
+	gob.Register(SandState{})
+	gob.Register(SnowState{})
+}
+
+type StoneState struct {
+	ConnectedBlockState
+	CollidableBlockState
+}
+
+type SandState struct {
+	BaseBlockState
+}
+
+type SnowState struct {
+	BaseBlockState
+	TexturedBlockState
+}
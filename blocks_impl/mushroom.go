@@ -1,17 +1,24 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
-
 	"github.com/3elDU/bamboo/types"
 
 	"github.com/3elDU/bamboo/assets"
 )
 
 func init() {
-	gob.Register(MushroomState{})
-	types.NewRedMushroomBlock = NewRedMushroomBlock
-	types.NewWhiteMushroomBlock = NewWhiteMushroomBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.RedMushroomBlock,
+		Key:   "bamboo:red_mushroom",
+		New:   NewRedMushroomBlock,
+		State: MushroomState{},
+	})
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.WhiteMushroomBlock,
+		Key:   "bamboo:white_mushroom",
+		New:   NewWhiteMushroomBlock,
+		State: MushroomState{},
+	})
 }
 
 type MushroomState struct {
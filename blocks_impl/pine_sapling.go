@@ -1,18 +1,20 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
 	"fmt"
 	"math/rand"
 
 	"github.com/3elDU/bamboo/assets"
-	"github.com/3elDU/bamboo/scene_manager"
 	"github.com/3elDU/bamboo/types"
 )
 
 func init() {
-	gob.Register(PineSaplingBlockState{})
-	types.NewPineSaplingBlock = NewPineSaplingBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.PineSaplingBlock,
+		Key:   "bamboo:pine_sapling",
+		New:   NewPineSaplingBlock,
+		State: PineSaplingBlockState{},
+	})
 }
 
 type PineSaplingBlockState struct {
@@ -42,16 +44,16 @@ func NewPineSaplingBlock() types.Block {
 func (block *PineSaplingBlock) setStage(stage int) {
 	block.stage = stage
 	if block.stage == 5 {
-		types.GetCurrentWorld().SetBlock(uint64(block.x), uint64(block.y), types.NewPineTreeBlock())
+		types.GetCurrentWorld().SetBlock(uint64(block.x), uint64(block.y), types.NewBlock(types.PineTreeBlock))
 	} else {
 		block.tex = assets.Texture(fmt.Sprintf("sapling_block%v", block.stage))
 	}
 }
 
-func (block *PineSaplingBlock) Update(world types.World) {
-	// every 1800 ticks (30 seconds) sapling has 50% chance to grow to next stage
-	// each stage will take ~60 seconds, so full tree would grow in ~3.5 minutes
-	if scene_manager.Ticks()%180 == 0 && rand.Intn(20) == 0 {
+func (block *PineSaplingBlock) RandomTick(world types.World) {
+	// sapling gets a random tick every ~85 ticks, and grows with 1/42 chance,
+	// so each stage will take ~60 seconds, and full tree would grow in ~4 minutes
+	if rand.Intn(42) == 0 {
 		block.setStage(block.stage + 1)
 		block.parentChunk.MarkAsModified()
 	}
@@ -73,10 +75,10 @@ func (blok *PineSaplingBlock) ToolStrengthRequired() types.ToolStrength {
 }
 func (block *PineSaplingBlock) Break() {
 	types.GetPlayerInventory().AddItem(types.ItemSlot{
-		Item:     types.NewPineSaplingItem(),
+		Item:     types.NewItem(types.PineSaplingItem),
 		Quantity: 1,
 	})
-	types.GetCurrentWorld().SetBlock(uint64(block.x), uint64(block.y), types.NewGrassBlock())
+	types.GetCurrentWorld().SetBlock(uint64(block.x), uint64(block.y), types.NewBlock(types.GrassBlock))
 }
 
 func (block *PineSaplingBlock) State() interface{} {
@@ -1,7 +1,6 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
 	"math/rand"
 
 	"github.com/3elDU/bamboo/assets"
@@ -9,8 +8,12 @@ import (
 )
 
 func init() {
-	gob.Register(PineTreeState{})
-	types.NewPineTreeBlock = NewPineTreeBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.PineTreeBlock,
+		Key:   "bamboo:pine_tree",
+		New:   NewPineTreeBlock,
+		State: PineTreeState{},
+	})
 }
 
 type PineTreeState struct {
@@ -29,7 +32,7 @@ func NewPineTreeBlock() types.Block {
 			baseBlock: baseBlock{
 				blockType: types.PineTreeBlock,
 			},
-			tex:        assets.ConnectedTexture("pine", false, false, false, false),
+			tex:        assets.ConnectedTexture("pine"),
 			connectsTo: []types.BlockType{types.PineTreeBlock},
 		},
 		collidableBlock: collidableBlock{
@@ -47,11 +50,11 @@ func (blok *PineTreeBlock) ToolStrengthRequired() types.ToolStrength {
 func (b *PineTreeBlock) Break() {
 	if types.GetPlayerInventory().AddItems(
 		// 1-2 saplings
-		types.NewItemSlot(types.NewPineSaplingItem(), uint8(1+rand.Intn(2))),
+		types.NewItemSlot(types.NewItem(types.PineSaplingItem), uint8(1+rand.Intn(2))),
 		// 1-2 sticks
-		types.NewItemSlot(types.NewStickItem(), uint8(1+rand.Intn(2))),
+		types.NewItemSlot(types.NewItem(types.StickItem), uint8(1+rand.Intn(2))),
 	) {
-		types.GetCurrentWorld().SetBlock(uint64(b.x), uint64(b.y), types.NewGrassBlock())
+		types.GetCurrentWorld().SetBlock(uint64(b.x), uint64(b.y), types.NewBlock(types.GrassBlock))
 	}
 }
 
@@ -6,7 +6,11 @@ import (
 )
 
 func init() {
-	types.NewPitBlock = NewPitBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type: types.PitBlock,
+		Key:  "bamboo:pit",
+		New:  NewPitBlock,
+	})
 }
 
 type PitBlock struct {
@@ -21,7 +25,7 @@ func NewPitBlock() types.Block {
 				blockType: types.PitBlock,
 			},
 			connectsTo: []types.BlockType{types.PitBlock},
-			tex:        assets.ConnectedTexture("pit", true, true, true, true),
+			tex:        assets.ConnectedTexture("pit"),
 		},
 		collidableBlock: collidableBlock{
 			collidable: true,
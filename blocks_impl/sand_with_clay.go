@@ -6,7 +6,11 @@ import (
 )
 
 func init() {
-	types.NewSandWithClayBlock = NewSandWithClayBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type: types.SandWithClayBlock,
+		Key:  "bamboo:sand_with_clay",
+		New:  NewSandWithClayBlock,
+	})
 }
 
 type SandWithClayBlock struct {
@@ -38,10 +42,10 @@ func (b *SandWithClayBlock) ToolStrengthRequired() types.ToolStrength {
 }
 func (b *SandWithClayBlock) Break() {
 	if types.GetPlayerInventory().AddItem(types.ItemSlot{
-		Item:     types.NewClayItem(),
+		Item:     types.NewItem(types.ClayItem),
 		Quantity: 1,
 	}) {
-		types.GetCurrentWorld().SetBlock(uint64(b.x), uint64(b.y), types.NewSandBlock())
+		types.GetCurrentWorld().SetBlock(uint64(b.x), uint64(b.y), types.NewBlock(types.SandBlock))
 	}
 }
 
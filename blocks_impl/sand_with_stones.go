@@ -6,7 +6,11 @@ import (
 )
 
 func init() {
-	types.NewSandWithStonesBlock = NewSandWithStonesBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type: types.SandWithStonesBlock,
+		Key:  "bamboo:sand_with_stones",
+		New:  NewSandWithStonesBlock,
+	})
 }
 
 type SandWithStonesBlock struct {
@@ -38,10 +42,10 @@ func (b *SandWithStonesBlock) ToolStrengthRequired() types.ToolStrength {
 }
 func (b *SandWithStonesBlock) Break() {
 	if types.GetPlayerInventory().AddItem(types.ItemSlot{
-		Item:     types.NewFlintItem(),
+		Item:     types.NewItem(types.FlintItem),
 		Quantity: 1,
 	}) {
-		types.GetCurrentWorld().SetBlock(uint64(b.x), uint64(b.y), types.NewSandBlock())
+		types.GetCurrentWorld().SetBlock(uint64(b.x), uint64(b.y), types.NewBlock(types.SandBlock))
 	}
 }
 
@@ -1,15 +1,17 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
-
 	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
 )
 
 func init() {
-	gob.Register(ShortGrassState{})
-	types.NewShortGrassBlock = NewShortGrassBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.ShortGrassBlock,
+		Key:   "bamboo:short_grass",
+		New:   NewShortGrassBlock,
+		State: ShortGrassState{},
+	})
 }
 
 type ShortGrassState struct {
@@ -26,7 +28,7 @@ func NewShortGrassBlock() types.Block {
 			baseBlock: baseBlock{
 				blockType: types.ShortGrassBlock,
 			},
-			tex: assets.ConnectedTexture("short_grass", true, true, true, true),
+			tex: assets.ConnectedTexture("short_grass"),
 			connectsTo: []types.BlockType{
 				types.ShortGrassBlock,
 				types.FlowersBlock,
@@ -43,7 +45,7 @@ func (b *ShortGrassBlock) ToolStrengthRequired() types.ToolStrength {
 	return types.ToolStrengthBareHand
 }
 func (b *ShortGrassBlock) Break() {
-	types.GetCurrentWorld().SetBlock(uint64(b.x), uint64(b.y), types.NewGrassBlock())
+	types.GetCurrentWorld().SetBlock(uint64(b.x), uint64(b.y), types.NewBlock(types.GrassBlock))
 }
 
 func (b *ShortGrassBlock) State() interface{} {
@@ -1,16 +1,18 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
-
 	"github.com/3elDU/bamboo/types"
 
 	"github.com/3elDU/bamboo/assets"
 )
 
 func init() {
-	gob.Register(TallGrassState{})
-	types.NewTallGrassBlock = NewTallGrassBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.TallGrassBlock,
+		Key:   "bamboo:tall_grass",
+		New:   NewTallGrassBlock,
+		State: TallGrassState{},
+	})
 }
 
 type TallGrassState struct {
@@ -2,11 +2,9 @@ package blocks_impl
 
 import (
 	"log"
-	"math"
 
 	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type TexturedBlockState struct {
@@ -20,27 +18,14 @@ type texturedBlock struct {
 	rotation float64 // in degrees
 }
 
-func (b *texturedBlock) Render(_ types.World, screen *ebiten.Image, pos types.Vec2f, _ bool) {
-	opts := &ebiten.DrawImageOptions{}
-
-	if b.rotation != 0 {
-		bounds := b.tex.Texture().Bounds()
-		// Move image half a texture size, so that rotation origin will be in the center
-		opts.GeoM.Translate(float64(-bounds.Dx()/2), float64(-bounds.Dy()/2))
-		opts.GeoM.Rotate(b.rotation * (math.Pi / 180))
-		pos.X += float64(bounds.Dx() / 2)
-		pos.Y += float64(bounds.Dy() / 2)
-	}
-
-	opts.GeoM.Translate(pos.X, pos.Y)
-
-	screen.DrawImage(b.tex.Texture(), opts)
-}
-
 func (b *texturedBlock) TextureName() string {
 	return b.tex.Name()
 }
 
+func (b *texturedBlock) TextureRotation() float64 {
+	return b.rotation
+}
+
 func (b *texturedBlock) State() interface{} {
 	return TexturedBlockState{
 		Name:     b.tex.Name(),
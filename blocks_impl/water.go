@@ -1,15 +1,17 @@
 package blocks_impl
 
 import (
-	"encoding/gob"
-
 	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
 )
 
 func init() {
-	gob.Register(WaterState{})
-	types.NewWaterBlock = NewWaterBlock
+	types.RegisterBlock(types.BlockDefinition{
+		Type:  types.WaterBlock,
+		Key:   "bamboo:water",
+		New:   NewWaterBlock,
+		State: WaterState{},
+	})
 }
 
 type WaterState struct {
@@ -28,7 +30,7 @@ func NewWaterBlock() types.Block {
 			baseBlock: baseBlock{
 				blockType: types.WaterBlock,
 			},
-			tex:        assets.ConnectedTexture("lake", false, false, false, false),
+			tex:        assets.ConnectedTexture("lake"),
 			connectsTo: []types.BlockType{types.WaterBlock},
 		},
 		collidableBlock: collidableBlock{
@@ -0,0 +1,19 @@
+// Package clock keeps the game tick counter.
+// It is separate from the scene manager, so that the world and blocks can be used
+// without a graphics context (e.g. in command-line tools).
+package clock
+
+import "sync/atomic"
+
+// Accessed atomically, because chunks read it from worldgen workers
+var tickCounter uint64
+
+// Ticks returns the number of ticks since the game was started. 1 second == 60 ticks
+func Ticks() uint64 {
+	return atomic.LoadUint64(&tickCounter)
+}
+
+// Tick advances the counter. Called by the scene manager after each update
+func Tick() {
+	atomic.AddUint64(&tickCounter, 1)
+}
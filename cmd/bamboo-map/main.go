@@ -0,0 +1,317 @@
+// bamboo-map renders a world to a PNG image
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"os"
+
+	"github.com/3elDU/bamboo/assets"
+	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/world"
+	"github.com/3elDU/bamboo/world_type"
+	"github.com/3elDU/bamboo/worldgen"
+	"github.com/google/uuid"
+
+	// imports for side effects
+	_ "github.com/3elDU/bamboo/blocks_impl"
+	_ "github.com/3elDU/bamboo/items_impl"
+)
+
+const usage = `Usage:
+  bamboo-map [options] -save <BaseUUID> [-world <UUID>]   render a saved world
+  bamboo-map [options] -seed <seed> [-type overworld|cave|cave2|cave3]   render a freshly generated world
+
+Chunks, that are missing from the save, are generated.
+
+Options:
+`
+
+// Drawn for blocks without a texture
+var missingTextureColor = color.RGBA{R: 255, G: 0, B: 255, A: 255}
+
+func main() {
+	var (
+		dir       = flag.String("dir", ".", "game directory, containing the saves folder")
+		saveID    = flag.String("save", "", "BaseUUID of the save to render")
+		worldID   = flag.String("world", "", "UUID of the world in the save (default: the overworld)")
+		seed      = flag.Int64("seed", 0, "seed of the world to generate, when -save is not given")
+		worldType = flag.String("type", "overworld", "type of the world to generate: overworld, cave, cave2 or cave3")
+		preset    = flag.String("preset", worldgen.DefaultPresetName, "name of the worldgen preset, when generating the overworld")
+		size      = flag.Uint64("size", 0, "size of the overworld to generate, in blocks (default: the usual size)")
+		shape     = flag.String("shape", types.SingleIslandShape.String(), "shape of the overworld to generate: single island, archipelago or continent")
+		mode      = flag.String("mode", "pixel", "pixel: one pixel per block, texture: one 16x16 texture per block")
+		area      = flag.String("area", "", "part of the world to render, in chunks: x,y,width,height (default: whole world)")
+		out       = flag.String("out", "map.png", "output file")
+	)
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// save paths in config are relative to the game directory
+	if err := os.Chdir(*dir); err != nil {
+		log.Fatalf("failed to open game directory: %v", err)
+	}
+
+	var source *chunkSource
+	if *saveID != "" {
+		source = saveSource(*saveID, *worldID)
+	} else {
+		source = seedSource(*seed, *worldType, *preset, *size, *shape)
+	}
+
+	sizeInChunks := types.Vec2u{X: source.metadata.Size.X / 16, Y: source.metadata.Size.Y / 16}
+	x, y, w, h := uint64(0), uint64(0), sizeInChunks.X, sizeInChunks.Y
+	if *area != "" {
+		if _, err := fmt.Sscanf(*area, "%d,%d,%d,%d", &x, &y, &w, &h); err != nil {
+			log.Fatalf("invalid area %q: %v", *area, err)
+		}
+	}
+
+	var blockSize int
+	switch *mode {
+	case "pixel":
+		blockSize = 1
+	case "texture":
+		blockSize = 16
+	default:
+		log.Fatalf("unknown mode %q", *mode)
+	}
+
+	img := renderMap(source, x, y, w, h, blockSize)
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("failed to create %v: %v", *out, err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		log.Fatalf("failed to write %v: %v", *out, err)
+	}
+
+	log.Printf("rendered %vx%v chunks to %v", w, h, *out)
+}
+
+// Provides chunks of the world, loading them from the save, or generating them
+type chunkSource struct {
+	metadata  types.Save
+	generator types.WorldGenerator
+	// Load chunks from the save before generating them
+	fromSave bool
+
+	chunks map[types.Vec2u]*world.Chunk
+}
+
+func saveSource(saveID, worldID string) *chunkSource {
+	baseUUID, err := uuid.Parse(saveID)
+	if err != nil {
+		log.Fatalf("invalid save UUID %q: %v", saveID, err)
+	}
+
+	var metadata types.Save
+	if worldID == "" {
+		// find the overworld
+		worlds, err := world.ListWorlds(baseUUID)
+		if err != nil {
+			log.Fatalf("failed to list worlds: %v", err)
+		}
+		found := false
+		for _, w := range worlds {
+			if w.WorldType == world_type.Overworld {
+				metadata, found = w, true
+			}
+		}
+		if !found {
+			log.Fatalf("save %v has no overworld", baseUUID)
+		}
+	} else {
+		id, err := uuid.Parse(worldID)
+		if err != nil {
+			log.Fatalf("invalid world UUID %q: %v", worldID, err)
+		}
+		metadata, err = world.ReadMetadata(baseUUID, id)
+		if err != nil {
+			log.Fatalf("failed to read world metadata: %v", err)
+		}
+	}
+
+	return &chunkSource{
+		metadata:  metadata,
+		generator: worldgen.NewWorldgenForWorld(metadata),
+		fromSave:  true,
+		chunks:    make(map[types.Vec2u]*world.Chunk),
+	}
+}
+
+func seedSource(seed int64, worldType, presetName string, size uint64, shape string) *chunkSource {
+	metadata := types.Save{Seed: seed}
+	found := false
+	for _, t := range []world_type.WorldType{world_type.Overworld, world_type.Cave, world_type.Cave2, world_type.Cave3} {
+		if t.String() == worldType {
+			metadata.WorldType, found = t, true
+		}
+	}
+	if !found {
+		log.Fatalf("unknown world type %q", worldType)
+	}
+	metadata.Size = world.SizeForWorldType(metadata.WorldType)
+	if size != 0 {
+		if size%16 != 0 {
+			log.Fatalf("world size %v is not a multiple of the chunk size", size)
+		}
+		metadata.Size = types.Vec2u{X: size, Y: size}
+	}
+
+	found = false
+	for _, s := range []types.WorldShape{types.SingleIslandShape, types.ArchipelagoShape, types.ContinentShape} {
+		if s.String() == shape {
+			metadata.Shape, found = s, true
+		}
+	}
+	if !found {
+		log.Fatalf("unknown shape %q", shape)
+	}
+
+	found = false
+	for _, preset := range worldgen.LoadPresets() {
+		if preset.Name == presetName {
+			selected := preset
+			metadata.Preset, found = &selected, true
+		}
+	}
+	if !found {
+		log.Fatalf("unknown preset %q", presetName)
+	}
+
+	return &chunkSource{
+		metadata:  metadata,
+		generator: worldgen.NewWorldgenForWorld(metadata),
+		chunks:    make(map[types.Vec2u]*world.Chunk),
+	}
+}
+
+func (s *chunkSource) chunk(cx, cy uint64) *world.Chunk {
+	coords := types.Vec2u{X: cx, Y: cy}
+	if c, exists := s.chunks[coords]; exists {
+		return c
+	}
+
+	var c *world.Chunk
+	if s.fromSave {
+		loaded, err := world.LoadChunk(s.metadata, cx, cy)
+		if err != nil {
+			log.Printf("failed to load chunk %v, %v, generating it instead: %v", cx, cy, err)
+		}
+		c = loaded
+	}
+	if c == nil {
+		c = world.NewChunk(cx, cy)
+		s.generator.GenerateImmediately(c)
+	}
+
+	s.chunks[coords] = c
+	return c
+}
+
+// Returns nil for blocks outside of the world
+func (s *chunkSource) blockAt(bx, by int64) types.Block {
+	if bx < 0 || by < 0 || uint64(bx) >= s.metadata.Size.X || uint64(by) >= s.metadata.Size.Y {
+		return nil
+	}
+	return s.chunk(uint64(bx)/16, uint64(by)/16).At(uint(bx%16), uint(by%16))
+}
+
+// Returns the image of the block, as it would be drawn in the game
+func (s *chunkSource) blockImage(block types.Block) (image.Image, string) {
+	drawable, ok := block.(types.DrawableBlock)
+	if !ok {
+		return nil, ""
+	}
+
+	connected, ok := block.(types.ConnectedBlock)
+	if !ok {
+		img, _ := assets.Image(drawable.TextureName())
+		return img, drawable.TextureName()
+	}
+
+	coords := block.Coords()
+	var sides [4]bool
+	for i, side := range [4]types.Vec2i{{X: -1, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: 0, Y: 1}} {
+		neighbor := s.blockAt(int64(coords.X)+int64(side.X), int64(coords.Y)+int64(side.Y))
+		sides[i] = neighbor != nil && connected.ShouldConnect(neighbor.Type())
+	}
+	img, _ := assets.ConnectedImage(connected.TextureName(), sides)
+	return img, fmt.Sprintf("%v%v", connected.TextureName(), sides)
+}
+
+// Average color of the texture, ignoring transparent pixels
+func averageColor(img image.Image) color.RGBA {
+	var r, g, b, a uint64
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			cr, cg, cb, ca := img.At(x, y).RGBA()
+			// colors are alpha-premultiplied, so transparent pixels don't contribute
+			r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+		}
+	}
+	if a == 0 {
+		return color.RGBA{}
+	}
+	return color.RGBA{
+		R: uint8(r * 0xff / a),
+		G: uint8(g * 0xff / a),
+		B: uint8(b * 0xff / a),
+		A: 255,
+	}
+}
+
+func renderMap(source *chunkSource, x, y, w, h uint64, blockSize int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, int(w)*16*blockSize, int(h)*16*blockSize))
+	colorCache := make(map[string]color.RGBA)
+
+	for cy := y; cy < y+h; cy++ {
+		for cx := x; cx < x+w; cx++ {
+			c := source.chunk(cx, cy)
+
+			for bx := uint(0); bx < 16; bx++ {
+				for by := uint(0); by < 16; by++ {
+					px := (int(cx-x)*16 + int(bx)) * blockSize
+					py := (int(cy-y)*16 + int(by)) * blockSize
+					rect := image.Rect(px, py, px+blockSize, py+blockSize)
+
+					tex, key := source.blockImage(c.At(bx, by))
+					switch {
+					case tex == nil:
+						draw.Draw(img, rect, image.NewUniform(missingTextureColor), image.Point{}, draw.Src)
+					case blockSize == 1:
+						col, cached := colorCache[key]
+						if !cached {
+							col = averageColor(tex)
+							colorCache[key] = col
+						}
+						img.SetRGBA(px, py, col)
+					default:
+						draw.Draw(img, rect, tex, tex.Bounds().Min, draw.Over)
+					}
+				}
+			}
+		}
+
+		// chunks above the current row are not needed anymore
+		for coords := range source.chunks {
+			if coords.Y < cy {
+				delete(source.chunks, coords)
+			}
+		}
+	}
+
+	return img
+}
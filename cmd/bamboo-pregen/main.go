@@ -0,0 +1,109 @@
+// bamboo-pregen generates and saves chunks of a world ahead of time
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/3elDU/bamboo/world"
+	"github.com/3elDU/bamboo/world_type"
+	"github.com/3elDU/bamboo/worldgen"
+	"github.com/google/uuid"
+
+	// imports for side effects
+	_ "github.com/3elDU/bamboo/blocks_impl"
+	_ "github.com/3elDU/bamboo/items_impl"
+)
+
+const usage = `Usage: bamboo-pregen [options] -save <BaseUUID> [-world <UUID>]
+
+Generates and saves every chunk in the area, that isn't saved yet.
+If interrupted, running it again continues where it stopped.
+The world must not be open in the game at the same time.
+
+Options:
+`
+
+func main() {
+	var (
+		dir     = flag.String("dir", ".", "game directory, containing the saves folder")
+		saveID  = flag.String("save", "", "BaseUUID of the save")
+		worldID = flag.String("world", "", "UUID of the world in the save (default: the overworld)")
+		radius  = flag.Uint64("radius", 16, "radius around the spawn point, in chunks")
+		area    = flag.String("area", "", "rectangle to generate instead of the radius, in chunks: x,y,width,height")
+	)
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// save paths in config are relative to the game directory
+	if err := os.Chdir(*dir); err != nil {
+		log.Fatalf("failed to open game directory: %v", err)
+	}
+
+	baseUUID, err := uuid.Parse(*saveID)
+	if err != nil {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	worlds, err := world.ListWorlds(baseUUID)
+	if err != nil {
+		log.Fatalf("failed to list worlds: %v", err)
+	}
+	found := false
+	for _, w := range worlds {
+		if (*worldID == "" && w.WorldType == world_type.Overworld) || w.UUID.String() == *worldID {
+			found = true
+
+			generator := worldgen.NewWorldgenForWorld(w)
+			var pregenerator *world.Pregenerator
+			if *area != "" {
+				var x, y, width, height uint64
+				if _, err := fmt.Sscanf(*area, "%d,%d,%d,%d", &x, &y, &width, &height); err != nil {
+					log.Fatalf("invalid area %q: %v", *area, err)
+				}
+				pregenerator = world.NewPregenerator(w, generator, x, y, width, height)
+			} else {
+				pregenerator = world.NewSpawnPregenerator(w, generator, *radius)
+			}
+
+			run(pregenerator)
+			break
+		}
+	}
+	if !found {
+		log.Fatalf("world not found in save %v", baseUUID)
+	}
+}
+
+func run(pregenerator *world.Pregenerator) {
+	// stop after the current chunk on Ctrl+C, so no chunk is left half-written
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	lastReport := time.Now()
+	for pregenerator.Step() {
+		select {
+		case <-interrupt:
+			done, total := pregenerator.Progress()
+			log.Printf("interrupted at %v/%v chunks; run again to continue", done, total)
+			os.Exit(1)
+		default:
+		}
+
+		if time.Since(lastReport) > time.Second {
+			done, total := pregenerator.Progress()
+			log.Printf("%v/%v chunks (%.1f%%)", done, total, float64(done)/float64(total)*100)
+			lastReport = time.Now()
+		}
+	}
+
+	log.Printf("done; generated %v chunks, %v were already saved", pregenerator.Generated, pregenerator.Skipped)
+}
@@ -0,0 +1,319 @@
+// bamboo-save inspects saves without starting the game
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/game/inventory"
+	"github.com/3elDU/bamboo/game/player"
+	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/world"
+	"github.com/3elDU/bamboo/world_type"
+	"github.com/google/uuid"
+
+	// imports for side effects
+	_ "github.com/3elDU/bamboo/blocks_impl"
+	_ "github.com/3elDU/bamboo/items_impl"
+)
+
+const usage = `Usage: bamboo-save [-dir <game directory>] <command> [arguments]
+
+Commands:
+  saves                                 list all saves
+  worlds    <save>                      list worlds in the save (overworld and caves)
+  metadata  <save> [world]              dump world metadata as JSON
+  player    <save>                      dump the player stack as JSON
+  inventory <save>                      dump inventory slots and item states as JSON
+  chunk     <save> <world> <cx> <cy>    print the chunk as a grid of block types
+  states    <save> <world> <cx> <cy>    dump block states of the chunk as JSON
+  validate  <save>                      try to load every saved chunk, reporting broken ones
+
+<save> is the BaseUUID of the save, <world> is the UUID of the world.
+`
+
+func main() {
+	dir := flag.String("dir", ".", "game directory, containing the saves folder")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+	}
+	flag.Parse()
+
+	// save paths in config are relative to the game directory
+	if err := os.Chdir(*dir); err != nil {
+		log.Fatalf("failed to open game directory: %v", err)
+	}
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "saves":
+		listSaves()
+	case "worlds":
+		listWorlds(parseUUID(arg(args, 1)))
+	case "metadata":
+		dumpMetadata(args[1:])
+	case "player":
+		stack, err := player.ReadPlayerStack(parseUUID(arg(args, 1)))
+		if err != nil {
+			log.Fatalf("failed to read the player stack: %v", err)
+		}
+		printJSON(stack)
+	case "inventory":
+		dumpInventory(parseUUID(arg(args, 1)))
+	case "chunk":
+		printChunk(readChunk(args))
+	case "states":
+		_, chunk := readChunk(args)
+		dumpStates(chunk)
+	case "validate":
+		if !validate(parseUUID(arg(args, 1))) {
+			os.Exit(1)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// Returns n-th argument, exiting with usage if it is missing
+func arg(args []string, n int) string {
+	if n >= len(args) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	return args[n]
+}
+
+func parseUUID(s string) uuid.UUID {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		log.Fatalf("invalid UUID %q: %v", s, err)
+	}
+	return id
+}
+
+func parseCoord(s string) uint64 {
+	c, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		log.Fatalf("invalid chunk coordinate %q: %v", s, err)
+	}
+	return c
+}
+
+func printJSON(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		log.Fatalf("failed to encode JSON: %v", err)
+	}
+}
+
+func listSaves() {
+	entries, err := os.ReadDir(config.WorldSaveDirectory)
+	if err != nil {
+		log.Fatalf("failed to read saves directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		baseUUID, err := uuid.Parse(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		worlds, err := world.ListWorlds(baseUUID)
+		if err != nil {
+			fmt.Printf("%v  (unreadable: %v)\n", baseUUID, err)
+			continue
+		}
+		name := "(no overworld)"
+		for _, w := range worlds {
+			if w.WorldType == world_type.Overworld {
+				name = w.Name
+			}
+		}
+		fmt.Printf("%v  %v  %v worlds\n", baseUUID, name, len(worlds))
+	}
+}
+
+func listWorlds(baseUUID uuid.UUID) {
+	worlds, err := world.ListWorlds(baseUUID)
+	if err != nil {
+		log.Fatalf("failed to list worlds: %v", err)
+	}
+
+	for _, w := range worlds {
+		chunks, err := world.SavedChunks(w)
+		if err != nil {
+			log.Printf("failed to list chunks of %v: %v", w.UUID, err)
+		}
+		fmt.Printf("%v  %-9v  seed %v  size %vx%v  %v chunks\n",
+			w.UUID, w.WorldType, w.Seed, w.Size.X, w.Size.Y, len(chunks))
+	}
+}
+
+func dumpMetadata(args []string) {
+	baseUUID := parseUUID(arg(args, 0))
+
+	if len(args) > 1 {
+		metadata, err := world.ReadMetadata(baseUUID, parseUUID(args[1]))
+		if err != nil {
+			log.Fatalf("failed to read metadata: %v", err)
+		}
+		printJSON(metadata)
+		return
+	}
+
+	worlds, err := world.ListWorlds(baseUUID)
+	if err != nil {
+		log.Fatalf("failed to list worlds: %v", err)
+	}
+	printJSON(worlds)
+}
+
+// Item type is printed by name, along with the state
+type jsonSlot struct {
+	Slot     int
+	Item     string
+	Quantity uint8
+	State    interface{}
+}
+
+func dumpInventory(baseUUID uuid.UUID) {
+	slots, err := inventory.ReadSavedSlots(baseUUID)
+	if err != nil {
+		log.Fatalf("failed to read the inventory: %v", err)
+	}
+
+	dumped := make([]jsonSlot, 0)
+	for i, slot := range slots {
+		if slot.Empty {
+			continue
+		}
+		name := fmt.Sprintf("unknown item %v", slot.Key())
+		if item, exists := types.NewItemByKey(slot.Key()); exists {
+			name = item.Name()
+		}
+		dumped = append(dumped, jsonSlot{Slot: i, Item: name, Quantity: slot.Quantity, State: slot.State})
+	}
+	printJSON(dumped)
+}
+
+// Reads the chunk from "<command> <save> <world> <cx> <cy>" arguments
+// Returns the chunk as it is stored on the disk, and the same chunk loaded the way the game loads it,
+// so blocks are shown after all migrations
+func readChunk(args []string) (*world.SavedChunk, *world.Chunk) {
+	baseUUID := parseUUID(arg(args, 1))
+	metadata, err := world.ReadMetadata(baseUUID, parseUUID(arg(args, 2)))
+	if err != nil {
+		log.Fatalf("failed to read world metadata: %v", err)
+	}
+	cx, cy := parseCoord(arg(args, 3)), parseCoord(arg(args, 4))
+
+	chunk, err := world.ReadSavedChunk(metadata, cx, cy)
+	if err != nil {
+		log.Fatalf("failed to read chunk %v, %v: %v", cx, cy, err)
+	}
+	if chunk == nil {
+		log.Fatalf("chunk %v, %v is not saved", cx, cy)
+	}
+
+	loaded, err := world.LoadChunk(metadata, cx, cy)
+	if err != nil {
+		log.Fatalf("failed to load chunk %v, %v: %v", cx, cy, err)
+	}
+	return chunk, loaded
+}
+
+func printChunk(saved *world.SavedChunk, chunk *world.Chunk) {
+	width := 0
+	for x := uint(0); x < 16; x++ {
+		for y := uint(0); y < 16; y++ {
+			if l := len(chunk.At(x, y).Type().String()); l > width {
+				width = l
+			}
+		}
+	}
+
+	fmt.Printf("chunk %v, %v (save format version %v)\n", saved.X, saved.Y, saved.Version)
+	for y := uint(0); y < 16; y++ {
+		row := make([]string, 16)
+		for x := uint(0); x < 16; x++ {
+			row[x] = fmt.Sprintf("%-*v", width, chunk.At(x, y).Type())
+		}
+		fmt.Println(strings.TrimRight(strings.Join(row, " "), " "))
+	}
+}
+
+type jsonBlock struct {
+	X, Y  int
+	Type  string
+	State interface{}
+}
+
+func dumpStates(chunk *world.Chunk) {
+	blocks := make([]jsonBlock, 0, 256)
+	for y := uint(0); y < 16; y++ {
+		for x := uint(0); x < 16; x++ {
+			block := chunk.At(x, y)
+			blocks = append(blocks, jsonBlock{
+				X: int(x), Y: int(y),
+				Type:  block.Type().String(),
+				State: block.State(),
+			})
+		}
+	}
+	printJSON(blocks)
+}
+
+// Returns false, if anything in the save is broken
+func validate(baseUUID uuid.UUID) bool {
+	ok := true
+
+	if _, err := player.ReadPlayerStack(baseUUID); err != nil {
+		fmt.Printf("player stack: %v\n", err)
+		ok = false
+	}
+	if _, err := inventory.ReadSavedSlots(baseUUID); err != nil {
+		fmt.Printf("inventory: %v\n", err)
+		ok = false
+	}
+
+	worlds, err := world.ListWorlds(baseUUID)
+	if err != nil {
+		log.Fatalf("failed to list worlds: %v", err)
+	}
+
+	for _, w := range worlds {
+		chunks, err := world.SavedChunks(w)
+		if err != nil {
+			fmt.Printf("world %v: %v\n", w.UUID, err)
+			ok = false
+			continue
+		}
+
+		broken := 0
+		for _, coords := range chunks {
+			if _, err := world.LoadChunk(w, coords.X, coords.Y); err != nil {
+				fmt.Printf("world %v: %v\n", w.UUID, err)
+				broken++
+			}
+		}
+		if broken > 0 {
+			ok = false
+		}
+		fmt.Printf("world %v (%v): %v chunks, %v broken\n", w.UUID, w.WorldType, len(chunks), broken)
+	}
+
+	return ok
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// ebiten initializes the window system as soon as it is imported,
+// so a tool that depends on it panics when there is no display
+func TestToolsDoNotImportEbiten(t *testing.T) {
+	for _, tool := range []string{"bamboo-map", "bamboo-save", "bamboo-pregen"} {
+		out, err := exec.Command("go", "list", "-deps", "./"+tool).Output()
+		if err != nil {
+			t.Skipf("go list failed: %v", err)
+		}
+
+		for _, pkg := range strings.Fields(string(out)) {
+			if strings.HasPrefix(pkg, "github.com/hajimehoshi/ebiten") {
+				t.Errorf("%v imports %v", tool, pkg)
+			}
+		}
+	}
+}
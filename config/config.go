@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // Set externally at build time
 var (
 	GitCommit string = "unknown"
@@ -22,16 +24,50 @@ const (
 	PlayerSpeed    float64 = 0.02
 	PlayerInfoFile         = "player.gob"
 
-	WorldSaveDirectory        = "./saves/"
-	WorldInfoFile             = "world.gob"
-	WorldAutosaveDelay uint64 = 3600
-	ChunkUnloadDelay   uint64 = 600
+	// Version of the save format. Bump it when saved state of a block or an item changes,
+	// and register a migration for the old state (types.RegisterBlockMigration, types.RegisterItemMigration)
+	// Version 3 stores state of each inventory slot as separately encoded bytes
+	SaveFormatVersion = 3
+
+	WorldSaveDirectory = "./saves/"
+	WorldInfoFile      = "world.gob"
+	// Snapshots of the saves are kept there, separately for each world
+	SnapshotDirectory = "./snapshots/"
+	// Take a snapshot of the world each time it is loaded
+	SnapshotBeforeLoad = true
+	// How many of the newest snapshots are kept, when old ones are pruned
+	SnapshotRetention = 5
+	// Exported worlds are written there, and imported from there
+	ExportDirectory = "./exports/"
+	// Corrupted save data is moved there
+	QuarantineDirectory        = "corrupt"
+	WorldAutosaveDelay  uint64 = 3600
+	ChunkUnloadDelay    uint64 = 600
+	// Number of goroutines generating chunks. 0 means one for each CPU core, except for one
+	WorldgenWorkers = 0
+	// Radius around the spawn point in chunks, that is pre-generated by the debug action
+	PregenerateRadius = 24
+	// How long the pre-generation may run each tick, when it is started in-game
+	PregenerateTimePerTick = 8 * time.Millisecond
+	// Width and height of a region file, in chunks
+	RegionSize = 32
+	// Compression used for newly saved chunks, unless the world has its own codec selected.
+	// One of "none", "gzip", "zstd", or any other codec registered with world.RegisterCodec()
+	ChunkCodec = "gzip"
+	// Number of random blocks in each loaded chunk, that get a random tick every tick.
+	// On average, each block is picked once in 256 / RandomTicksPerChunk ticks.
+	RandomTicksPerChunk = 3
 
 	InventoryFile       = "inventory.gob"
 	SlotSize      uint8 = 50
 
 	UIScaling float64 = 2
 
-	OverworldSize = 1024
-	Cave1Size     = 256
+	// Overworld size can be picked, when creating a new world
+	SmallOverworldSize = 512
+	OverworldSize      = 1024
+	LargeOverworldSize = 2048
+	Cave1Size          = 256
+	Cave2Size          = 192
+	Cave3Size          = 128
 )
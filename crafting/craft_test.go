@@ -0,0 +1,126 @@
+package crafting
+
+import (
+	"testing"
+
+	"github.com/3elDU/bamboo/game/inventory"
+	_ "github.com/3elDU/bamboo/items_impl"
+	"github.com/3elDU/bamboo/types"
+)
+
+func itemType(t *testing.T, key string) types.ItemType {
+	t.Helper()
+
+	itemType, exists := types.ItemTypeByKey(key)
+	if !exists {
+		t.Fatalf("item %q is not registered", key)
+	}
+	return itemType
+}
+
+func newSlot(t *testing.T, key string, quantity uint8) types.ItemSlot {
+	return types.NewItemSlot(types.NewItem(itemType(t, key)), quantity)
+}
+
+// Inventory with a clay pickaxe in the first slot, and clay in the second one
+func newToolInventory(t *testing.T) *inventory.Inventory {
+	inv := inventory.NewInventory()
+	if !inv.AddItems(newSlot(t, "bamboo:clay_pickaxe", 1), newSlot(t, "bamboo:clay", 10)) {
+		t.Fatal("failed to fill the inventory")
+	}
+	return inv
+}
+
+// Crushes clay with a pickaxe, taking the given durability from it
+func toolCraft(t *testing.T, durability int) types.Craft {
+	return types.Craft{
+		Name:        "Test",
+		Ingredients: []types.CraftIngredient{{Type: itemType(t, "bamboo:clay"), Amount: 1}},
+		Tools:       []types.CraftTool{{Type: itemType(t, "bamboo:clay_pickaxe"), Durability: durability}},
+		Results:     []types.CraftIngredient{{Type: itemType(t, "bamboo:flint"), Amount: 1}},
+	}
+}
+
+func TestCraftToolLosesDurability(t *testing.T) {
+	inv := newToolInventory(t)
+	pickaxe := inv.Slots[0].Item.(types.IDurableItem)
+	durability := pickaxe.Durability()
+
+	if !toolCraft(t, 5).Craft() {
+		t.Fatal("craft failed")
+	}
+	if inv.Slots[0].Empty || inv.Slots[0].Item != pickaxe {
+		t.Fatal("the tool was consumed")
+	}
+	if pickaxe.Durability() != durability-5 {
+		t.Errorf("expected durability %v, got %v", durability-5, pickaxe.Durability())
+	}
+	if !inv.HasItemOfType(itemType(t, "bamboo:flint"), 1) {
+		t.Error("the result wasn't added")
+	}
+}
+
+func TestCraftToolBreaks(t *testing.T) {
+	inv := newToolInventory(t)
+	durability := inv.Slots[0].Item.(types.IDurableItem).Durability()
+
+	if toolCraft(t, durability+1).AbleToCraft() {
+		t.Fatal("craft is possible with a tool, that doesn't have enough durability")
+	}
+	if !toolCraft(t, durability).Craft() {
+		t.Fatal("craft failed")
+	}
+	if inv.HasItemOfType(itemType(t, "bamboo:clay_pickaxe"), 1) {
+		t.Error("the broken tool is still in the inventory")
+	}
+	if toolCraft(t, 1).AbleToCraft() {
+		t.Error("craft is possible without the tool")
+	}
+}
+
+func TestCraftResultsDontFit(t *testing.T) {
+	inv := inventory.NewInventory()
+	inv.AddItems(
+		newSlot(t, "bamboo:clay", 1),
+		newSlot(t, "bamboo:coal", 50),
+		newSlot(t, "bamboo:raw_iron", 50),
+		newSlot(t, "bamboo:raw_gold", 50),
+	)
+
+	// each of the results fits into the last empty slot, but not both of them
+	craft := types.Craft{
+		Name:        "Test",
+		Ingredients: []types.CraftIngredient{{Type: itemType(t, "bamboo:clay"), Amount: 1}},
+		Results: []types.CraftIngredient{
+			{Type: itemType(t, "bamboo:stick"), Amount: 1},
+			{Type: itemType(t, "bamboo:flint"), Amount: 1},
+		},
+	}
+	if craft.Craft() {
+		t.Fatal("craft succeeded, though its results don't fit")
+	}
+	if !inv.HasItemOfType(itemType(t, "bamboo:clay"), 1) {
+		t.Error("ingredients were consumed")
+	}
+
+	// after one slot is freed, both results fit
+	inv.RemoveItemByType(itemType(t, "bamboo:coal"), 50)
+	if !craft.Craft() {
+		t.Fatal("craft failed")
+	}
+	for _, key := range []string{"bamboo:stick", "bamboo:flint"} {
+		if !inv.HasItemOfType(itemType(t, key), 1) {
+			t.Errorf("result %q wasn't added", key)
+		}
+	}
+}
+
+func TestToolsDontStack(t *testing.T) {
+	inv := newToolInventory(t)
+	if !inv.AddItem(newSlot(t, "bamboo:clay_pickaxe", 1)) {
+		t.Fatal("failed to add the second tool")
+	}
+	if inv.Slots[0].Quantity != 1 || inv.Slots[2].Empty {
+		t.Error("the second tool was stacked onto the first one")
+	}
+}
@@ -0,0 +1,149 @@
+// Recipes are described in JSON files in assets/definitions/recipes.
+// Each file contains a list of recipes.
+
+package crafting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/3elDU/bamboo/assets"
+	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/types"
+)
+
+// A list of all available crafts.
+// Filled on startup by types.CheckRegistries(), once all items are registered.
+var Crafts []types.Craft
+
+func init() {
+	recipes := make([]recipeData, 0)
+	for _, file := range assets.Definitions("recipes") {
+		var fileRecipes []recipeData
+
+		decoder := json.NewDecoder(bytes.NewReader(file.Data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&fileRecipes); err != nil {
+			log.Panicf("invalid recipes file %v - %v", file.Path, err)
+		}
+		recipes = append(recipes, fileRecipes...)
+	}
+
+	// item keys can only be resolved after all items are registered
+	types.AddRegistryCheck(func() error {
+		crafts := make([]types.Craft, 0, len(recipes))
+		for _, recipe := range recipes {
+			craft, err := recipe.resolve()
+			if err != nil {
+				return fmt.Errorf("recipe %q - %v", recipe.Name, err)
+			}
+			crafts = append(crafts, craft)
+		}
+		Crafts = crafts
+		return nil
+	})
+}
+
+type itemAmountData struct {
+	Item   string `json:"item"`
+	Amount int    `json:"amount"`
+}
+
+type toolData struct {
+	Item       string `json:"item"`
+	Durability int    `json:"durability"`
+}
+
+type stationData struct {
+	Blocks   []string `json:"blocks"`
+	Distance int      `json:"distance"`
+	Lit      bool     `json:"lit"`
+}
+
+// Recipe, as it is written in the JSON file
+type recipeData struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Stations    []stationData    `json:"stations"`
+	Ingredients []itemAmountData `json:"ingredients"`
+	Tools       []toolData       `json:"tools"`
+	Results     []itemAmountData `json:"results"`
+}
+
+func resolveItem(key string) (types.ItemType, error) {
+	itemType, exists := types.ItemTypeByKey(key)
+	if !exists {
+		return 0, fmt.Errorf("unknown item %q", key)
+	}
+	return itemType, nil
+}
+
+func resolveItemAmounts(items []itemAmountData) ([]types.CraftIngredient, error) {
+	resolved := make([]types.CraftIngredient, 0, len(items))
+	for _, item := range items {
+		itemType, err := resolveItem(item.Item)
+		if err != nil {
+			return nil, err
+		}
+		if item.Amount <= 0 || item.Amount > int(config.SlotSize) {
+			return nil, fmt.Errorf("amount of %q must be between 1 and %v", item.Item, config.SlotSize)
+		}
+		resolved = append(resolved, types.CraftIngredient{Type: itemType, Amount: item.Amount})
+	}
+	return resolved, nil
+}
+
+// Converts the recipe into types.Craft, checking that all items and blocks exist
+func (recipe recipeData) resolve() (types.Craft, error) {
+	craft := types.Craft{
+		Name:        recipe.Name,
+		Description: recipe.Description,
+	}
+	if recipe.Name == "" {
+		return craft, fmt.Errorf("recipe has no name")
+	}
+	if len(recipe.Results) == 0 {
+		return craft, fmt.Errorf("recipe has no results")
+	}
+
+	var err error
+	if craft.Ingredients, err = resolveItemAmounts(recipe.Ingredients); err != nil {
+		return craft, err
+	}
+	if craft.Results, err = resolveItemAmounts(recipe.Results); err != nil {
+		return craft, err
+	}
+
+	for _, tool := range recipe.Tools {
+		itemType, err := resolveItem(tool.Item)
+		if err != nil {
+			return craft, err
+		}
+		if _, durable := types.NewItem(itemType).(types.IDurableItem); !durable {
+			return craft, fmt.Errorf("%q is used as a tool, but doesn't have durability", tool.Item)
+		}
+		if tool.Durability <= 0 {
+			return craft, fmt.Errorf("tool %q must take some durability", tool.Item)
+		}
+		craft.Tools = append(craft.Tools, types.CraftTool{Type: itemType, Durability: tool.Durability})
+	}
+
+	for _, station := range recipe.Stations {
+		if len(station.Blocks) == 0 || station.Distance <= 0 {
+			return craft, fmt.Errorf("station must have blocks and a positive distance")
+		}
+		resolved := types.CraftStation{Distance: station.Distance, Lit: station.Lit}
+		for _, key := range station.Blocks {
+			blockType, exists := types.BlockTypeByKey(key)
+			if !exists {
+				return craft, fmt.Errorf("unknown block %q", key)
+			}
+			resolved.Blocks = append(resolved.Blocks, blockType)
+		}
+		craft.Stations = append(craft.Stations, resolved)
+	}
+
+	return craft, nil
+}
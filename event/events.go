@@ -1,6 +1,9 @@
 package event
 
-import "github.com/google/uuid"
+import (
+	"github.com/3elDU/bamboo/world_type"
+	"github.com/google/uuid"
+)
 
 // Enumeration with all declared event types
 const (
@@ -8,8 +11,12 @@ const (
 	CaveExit
 	// Reload graphic assets / etc.
 	Reload
+	// Player opened the furnace. Args is the furnace block, types.IFurnaceBlock
+	FurnaceOpen
 )
 
 type CaveEnteredArgs struct {
 	ID uuid.UUID
+	// Cave tier that is being entered
+	WorldType world_type.WorldType
 }
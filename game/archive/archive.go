@@ -0,0 +1,297 @@
+// Package archive exports saves to a single portable file, and imports them back
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/game/player"
+	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/util"
+	"github.com/google/uuid"
+)
+
+// Archive layout:
+//
+//	manifest.json - Manifest, describing the save
+//	save/         - contents of the saves/<BaseUUID> folder:
+//	                player.gob, inventory.gob, and a folder with world.gob and region files for each world
+const (
+	Extension    = ".bamboo"
+	manifestFile = "manifest.json"
+	saveFolder   = "save"
+
+	// Version of the archive layout itself. Save data inside has its own version.
+	ArchiveVersion = 1
+)
+
+type Manifest struct {
+	ArchiveVersion    int       `json:"archive_version"`
+	SaveFormatVersion int       `json:"save_format_version"`
+	ExportedAt        time.Time `json:"exported_at"`
+
+	Name     string    `json:"name"`
+	Seed     int64     `json:"seed"`
+	BaseUUID uuid.UUID `json:"base_uuid"`
+	// UUID of the overworld
+	Overworld uuid.UUID `json:"overworld"`
+	// UUIDs of all worlds in the save, including the overworld
+	Worlds []uuid.UUID `json:"worlds"`
+}
+
+// Export packs the save into an archive in config.ExportDirectory, and returns path to it.
+// The world must be saved to the disk before exporting.
+func Export(metadata types.Save) (string, error) {
+	saveDir := filepath.Join(config.WorldSaveDirectory, metadata.BaseUUID.String())
+
+	manifest := Manifest{
+		ArchiveVersion:    ArchiveVersion,
+		SaveFormatVersion: config.SaveFormatVersion,
+		ExportedAt:        time.Now(),
+
+		Name:      metadata.Name,
+		Seed:      metadata.Seed,
+		BaseUUID:  metadata.BaseUUID,
+		Overworld: metadata.UUID,
+	}
+
+	entries, err := os.ReadDir(saveDir)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		// each world has its own folder, named after its UUID
+		id, err := uuid.Parse(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		manifest.Worlds = append(manifest.Worlds, id)
+	}
+
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+
+	manifestWriter, err := w.Create(manifestFile)
+	if err != nil {
+		return "", err
+	}
+	encoder := json.NewEncoder(manifestWriter)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return "", err
+	}
+
+	if err := util.ZipDirectory(w, saveDir, saveFolder, nil); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(config.ExportDirectory,
+		fmt.Sprintf("%v_%v%v", fileName(metadata.Name), time.Now().Format("2006-01-02_15-04-05"), Extension))
+	if err := util.WriteFileAtomic(path, buf.Bytes()); err != nil {
+		return "", err
+	}
+
+	log.Printf("archive.Export() - exported %v to %v", metadata.BaseUUID, path)
+	return path, nil
+}
+
+// Replaces characters, that may not be allowed in file names
+func fileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// List returns paths to all archives in config.ExportDirectory
+func List() []string {
+	paths, _ := filepath.Glob(filepath.Join(config.ExportDirectory, "*"+Extension))
+	return paths
+}
+
+// ReadManifest reads and validates the manifest of the archive
+func ReadManifest(path string) (Manifest, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer r.Close()
+
+	return readManifest(&r.Reader)
+}
+
+func readManifest(r *zip.Reader) (Manifest, error) {
+	var manifest Manifest
+
+	f, err := r.Open(manifestFile)
+	if err != nil {
+		return manifest, fmt.Errorf("archive has no manifest - %v", err)
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(&manifest); err != nil {
+		return manifest, fmt.Errorf("failed to decode manifest - %v", err)
+	}
+
+	switch {
+	case manifest.ArchiveVersion != ArchiveVersion:
+		return manifest, fmt.Errorf("unsupported archive version %v", manifest.ArchiveVersion)
+	case manifest.SaveFormatVersion > config.SaveFormatVersion:
+		return manifest, fmt.Errorf("save was made with newer save format version %v", manifest.SaveFormatVersion)
+	case manifest.BaseUUID == uuid.Nil:
+		return manifest, errors.New("manifest has no BaseUUID")
+	}
+
+	overworldFound := false
+	for _, id := range manifest.Worlds {
+		if id == manifest.Overworld {
+			overworldFound = true
+		}
+
+		// every world listed in the manifest must have valid metadata
+		world, err := readWorldInfo(r, id)
+		if err != nil {
+			return manifest, err
+		}
+		if world.UUID != id || world.BaseUUID != manifest.BaseUUID {
+			return manifest, fmt.Errorf("metadata of world %v doesn't match the manifest", id)
+		}
+	}
+	if !overworldFound {
+		return manifest, errors.New("overworld is missing from the archive")
+	}
+
+	return manifest, nil
+}
+
+func readWorldInfo(r *zip.Reader, id uuid.UUID) (types.Save, error) {
+	var metadata types.Save
+
+	f, err := r.Open(saveFolder + "/" + id.String() + "/" + config.WorldInfoFile)
+	if err != nil {
+		return metadata, fmt.Errorf("world %v is missing from the archive - %v", id, err)
+	}
+	defer f.Close()
+
+	if err := gob.NewDecoder(f).Decode(&metadata); err != nil {
+		return metadata, fmt.Errorf("failed to decode metadata of world %v - %v", id, err)
+	}
+	return metadata, nil
+}
+
+// Import unpacks the archive into the saves folder, and returns metadata of the imported overworld.
+// If a save with the same BaseUUID already exists, the imported one gets a new BaseUUID.
+func Import(path string) (types.Save, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return types.Save{}, err
+	}
+	defer r.Close()
+
+	manifest, err := readManifest(&r.Reader)
+	if err != nil {
+		return types.Save{}, err
+	}
+
+	baseUUID := manifest.BaseUUID
+	if _, err := os.Stat(filepath.Join(config.WorldSaveDirectory, baseUUID.String())); err == nil {
+		baseUUID = uuid.New()
+		log.Printf("archive.Import() - save %v already exists, importing as %v", manifest.BaseUUID, baseUUID)
+	}
+
+	// Unpack outside of the saves folder first, so the world list doesn't pick up a half-imported save
+	stagingDir := filepath.Join(config.ExportDirectory, "importing")
+	os.RemoveAll(stagingDir)
+	defer os.RemoveAll(stagingDir)
+
+	if err := util.UnzipDirectory(&r.Reader, stagingDir, saveFolder); err != nil {
+		return types.Save{}, err
+	}
+
+	if baseUUID != manifest.BaseUUID {
+		if err := changeBaseUUID(stagingDir, manifest.Worlds, baseUUID); err != nil {
+			return types.Save{}, err
+		}
+	}
+
+	if err := os.MkdirAll(config.WorldSaveDirectory, os.ModePerm); err != nil {
+		return types.Save{}, err
+	}
+	if err := os.Rename(stagingDir, filepath.Join(config.WorldSaveDirectory, baseUUID.String())); err != nil {
+		return types.Save{}, err
+	}
+
+	overworld, err := readWorldInfo(&r.Reader, manifest.Overworld)
+	if err != nil {
+		return types.Save{}, err
+	}
+	overworld.BaseUUID = baseUUID
+
+	log.Printf("archive.Import() - imported %v from %v", baseUUID, path)
+	return overworld, nil
+}
+
+// Rewrites all references to the BaseUUID in the unpacked save
+func changeBaseUUID(saveDir string, worlds []uuid.UUID, baseUUID uuid.UUID) error {
+	files := util.NewAtomicWriter()
+	defer files.Discard()
+
+	for _, id := range worlds {
+		path := filepath.Join(saveDir, id.String(), config.WorldInfoFile)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var metadata types.Save
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&metadata); err != nil {
+			return err
+		}
+		metadata.BaseUUID = baseUUID
+
+		buf := new(bytes.Buffer)
+		if err := gob.NewEncoder(buf).Encode(metadata); err != nil {
+			return err
+		}
+		if err := files.Add(path, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	// the player stack keeps a copy of metadata for each world the player has entered
+	path := filepath.Join(saveDir, config.PlayerInfoFile)
+	if data, err := os.ReadFile(path); err == nil {
+		stack := new(player.Stack)
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(stack); err != nil {
+			return err
+		}
+		for _, p := range stack.Stack {
+			p.SelectedWorld.BaseUUID = baseUUID
+		}
+
+		buf := new(bytes.Buffer)
+		if err := gob.NewEncoder(buf).Encode(stack); err != nil {
+			return err
+		}
+		if err := files.Add(path, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return files.Commit()
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	"github.com/3elDU/bamboo/colors"
+	"github.com/3elDU/bamboo/scene_manager"
+	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/ui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Shown when the player interacts with a furnace
+type furnaceScene struct {
+	furnace types.IFurnaceBlock
+}
+
+func (scene *furnaceScene) Update() {
+	// If a player is more than 3 blocks away from the furnace, close the interface
+	if scene.furnace.Coords().DistanceTo(types.GetCurrentPlayer().Position()) > 3 {
+		scene_manager.HideOverlay()
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyP):
+		if types.GetPlayerInventory().SelectedSlot().Empty {
+			break
+		}
+
+		if scene.furnace.AddItem(types.GetPlayerInventory().ItemInHand()) {
+			types.GetPlayerInventory().SelectedSlot().RemoveItem(1)
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyT):
+		if types.GetPlayerInventory().AddItem(*scene.furnace.OutputSlot()) {
+			*scene.furnace.OutputSlot() = types.ItemSlot{Empty: true}
+		}
+	}
+}
+
+func (scene *furnaceScene) Draw(screen *ebiten.Image) {
+	ui.ImmediateDraw(screen, ui.Styled(ui.Padding(1.0,
+		ui.VStack().WithSpacing(2.0).WithChildren(
+			ui.Background(colors.C("blue"), ui.PaddingXY(1.0, 0.3,
+				ui.CustomLabel("Furnace", colors.C("white"), 1.5),
+			)),
+
+			ui.Tooltip(ui.VStack().WithSpacing(1.5).WithChildren(
+				ui.VStack(
+					ui.Label("Fuel"),
+					ui.LabelF("%v", scene.furnace.Energy()),
+				),
+				ui.VStack(
+					ui.Label("Items to smelt"),
+					ui.ItemSlot(scene.furnace.InputSlot()),
+				),
+				ui.VStack(
+					ui.Label("Smelted items"),
+					ui.ItemSlot(scene.furnace.OutputSlot()),
+				),
+			)),
+		),
+	)).WithTextColor(colors.C("white")))
+}
+
+func (scene *furnaceScene) Destroy() {
+
+}
@@ -1,10 +1,12 @@
 package game
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
-	"github.com/3elDU/bamboo/assets"
+	"github.com/3elDU/bamboo/clock"
 	"github.com/3elDU/bamboo/colors"
 	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/event"
@@ -12,10 +14,12 @@ import (
 	"github.com/3elDU/bamboo/game/inventory"
 	"github.com/3elDU/bamboo/game/player"
 	"github.com/3elDU/bamboo/scene_manager"
+	"github.com/3elDU/bamboo/textures"
 	"github.com/3elDU/bamboo/types"
 	"github.com/3elDU/bamboo/ui"
+	"github.com/3elDU/bamboo/util"
 	"github.com/3elDU/bamboo/world"
-	"github.com/3elDU/bamboo/world_type"
+	"github.com/3elDU/bamboo/world/render"
 	"github.com/MakeNowJust/heredoc"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -25,16 +29,19 @@ type Game struct {
 	paused    bool
 	pauseMenu *pauseMenu
 
-	world       *world.World
-	player      *player.Player
-	playerStack *player.Stack
-	inventory   *inventory.Inventory
+	world         *world.World
+	worldRenderer *render.WorldRenderer
+	player        *player.Player
+	playerStack   *player.Stack
+	inventory     *inventory.Inventory
 
 	craftingMenu *craftingMenu
 	compass      *ui.CompassComponent
 
 	// debug switches
 	superSpeed bool
+	// pre-generates chunks around the spawn point in the background, when not nil
+	pregenerator *world.Pregenerator
 }
 
 func newGame(gameWorld *world.World, playerStack *player.Stack, inventory *inventory.Inventory) *Game {
@@ -42,9 +49,10 @@ func newGame(gameWorld *world.World, playerStack *player.Stack, inventory *inven
 		pauseMenu:    newPauseMenu(),
 		craftingMenu: newCraftingMenu(),
 
-		world:       gameWorld,
-		playerStack: playerStack,
-		inventory:   inventory,
+		world:         gameWorld,
+		worldRenderer: render.NewWorldRenderer(),
+		playerStack:   playerStack,
+		inventory:     inventory,
 
 		compass: ui.NewCompassComponent(),
 	}
@@ -70,19 +78,69 @@ func NewGameScene(metadata types.Save) *Game {
 	return game
 }
 
-// Creates a game scene from existing world
-func LoadGameScene(metadata types.Save) *Game {
+// Creates a game scene from existing world.
+// Returns an error, if the save was made by a newer version of the game,
+// or if the world metadata is corrupted, and there is no copy to restore it from.
+func LoadGameScene(metadata types.Save) (*Game, error) {
 	// load the player stack first, to determine which world to load
-	loadedPlayer := player.LoadPlayerStack(metadata.BaseUUID)
-	loadedWorld := world.Load(metadata.BaseUUID, loadedPlayer.Top().SelectedWorld.UUID)
-	loadedInventory := inventory.LoadInventory(metadata.BaseUUID)
-	return newGame(loadedWorld, loadedPlayer, loadedInventory)
+	loadedPlayer, err := player.LoadPlayerStack(metadata.BaseUUID)
+	if err != nil {
+		return nil, err
+	}
+	loadedInventory, err := inventory.LoadInventory(metadata.BaseUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var loadedWorld *world.World
+	if len(loadedPlayer.Stack) > 0 {
+		loadedWorld, err = loadSelectedWorld(loadedPlayer.Top().SelectedWorld)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// The player stack is missing or corrupted, so start over at the overworld spawn point
+		log.Println("LoadGameScene() - player stack is empty, placing the player in the overworld")
+		loadedWorld, err = loadSelectedWorld(metadata)
+		if err != nil {
+			return nil, err
+		}
+		loadedPlayer.Push(player.NewPlayer(loadedWorld))
+	}
+
+	return newGame(loadedWorld, loadedPlayer, loadedInventory), nil
+}
+
+// Loads the world the player has selected.
+// If the world metadata is corrupted, it is restored from the copy kept in the player stack.
+func loadSelectedWorld(selected types.Save) (*world.World, error) {
+	loadedWorld, err := world.Load(selected.BaseUUID, selected.UUID)
+	if err == nil {
+		return loadedWorld, nil
+	}
+	if errors.Is(err, world.ErrNewerSaveVersion) {
+		return nil, err
+	}
+	// the copy is complete only if the player has been in that world
+	if selected.Size == (types.Vec2u{}) {
+		return nil, fmt.Errorf("%v; there is no copy of the metadata to restore it from", err)
+	}
+
+	log.Printf("loadSelectedWorld() - %v; restoring the metadata", err)
+	return world.NewWorld(selected), nil
 }
 
+// Saves the world, the player stack and the inventory as one consistent snapshot.
+// Files are committed in that order, so the player stack never refers to a world that wasn't written.
 func (game *Game) Save() {
-	game.world.Save()
-	game.playerStack.Save(game.world.Metadata())
-	game.inventory.Save(game.world.Metadata())
+	files := util.NewAtomicWriter()
+	game.world.SaveTo(files)
+	game.inventory.Save(files, game.world.Metadata())
+	game.playerStack.Save(files, game.world.Metadata())
+
+	if err := files.Commit(); err != nil {
+		log.Panicf("failed to save the game - %v", err)
+	}
 }
 
 func (game *Game) processInput() {
@@ -168,9 +226,16 @@ func (game *Game) processInput() {
 		// Make the player go faaaaaaaast
 		case ebiten.IsKeyPressed(ebiten.KeyF4) && inpututil.IsKeyJustPressed(ebiten.KeyS):
 			game.superSpeed = !game.superSpeed
+		// Pre-generate chunks around the spawn point on F4+P, pressing it again stops the pre-generation
+		case ebiten.IsKeyPressed(ebiten.KeyF4) && inpututil.IsKeyJustPressed(ebiten.KeyP):
+			if game.pregenerator == nil {
+				game.pregenerator = game.world.Pregenerate(config.PregenerateRadius)
+			} else {
+				game.pregenerator = nil
+			}
 
 		case inpututil.IsKeyJustPressed(ebiten.KeyF6):
-			game.world.SetBlock(lookingAt.X, lookingAt.Y, types.NewFurnaceBlock())
+			game.world.SetBlock(lookingAt.X, lookingAt.Y, types.NewBlock(types.FurnaceBlock))
 		}
 	}
 
@@ -191,8 +256,19 @@ func (game *Game) updateLogic() {
 	game.player.Update(game.superSpeed)
 	game.compass.Update()
 
+	if game.pregenerator != nil {
+		// spend only a part of the tick, so the game doesn't freeze
+		start := time.Now()
+		for time.Since(start) < config.PregenerateTimePerTick {
+			if !game.pregenerator.Step() {
+				game.pregenerator = nil
+				break
+			}
+		}
+	}
+
 	// perform autosave each N ticks
-	if scene_manager.Ticks()%config.WorldAutosaveDelay == 0 {
+	if clock.Ticks()%config.WorldAutosaveDelay == 0 {
 		game.Save()
 	}
 }
@@ -211,22 +287,28 @@ func (game *Game) handleEvents() {
 
 			// save the previous world before switching to a new one
 			game.Save()
+			game.pregenerator = nil
+			game.world.Unload()
 
-			caveID := ev.Args().(event.CaveEnteredArgs).ID
+			args := ev.Args().(event.CaveEnteredArgs)
 
 			metadata := types.Save{
 				Name:      game.world.Metadata().Name,
 				BaseUUID:  game.world.Metadata().BaseUUID,
-				UUID:      caveID,
-				Seed:      int64(caveID.ID()),
-				WorldType: world_type.Cave,
-				Size:      world.SizeForWorldType(world_type.Cave),
+				UUID:      args.ID,
+				Seed:      int64(args.ID.ID()),
+				WorldType: args.WorldType,
+				Size:      world.SizeForWorldType(args.WorldType),
+				Codec:     game.world.Metadata().Codec,
 			}
 
 			var newWorld *world.World
 			// Check if cave already exists on disk
 			if world.ExistsOnDisk(metadata) {
-				newWorld = world.Load(metadata.BaseUUID, metadata.UUID)
+				var err error
+				if newWorld, err = loadSelectedWorld(metadata); err != nil {
+					log.Panicf("failed to load the cave - %v", err)
+				}
 			} else {
 				newWorld = world.NewWorld(metadata)
 			}
@@ -237,18 +319,26 @@ func (game *Game) handleEvents() {
 			// don't place cave exit if that chunk already exists on disk, so we don't overwrite it
 			if !world.ChunkExistsOnDisk(newWorld.Metadata(), uint64(game.player.X+1)/16, uint64(game.player.Y)/16) {
 				// place a cave exit next to the player
-				newWorld.SetBlock(uint64(game.player.X)+1, uint64(game.player.Y), types.NewCaveExitBlock())
+				newWorld.SetBlock(uint64(game.player.X)+1, uint64(game.player.Y), types.NewBlock(types.CaveExitBlock))
 			}
 
 			game.world = newWorld
 			game.Save()
 		case event.CaveExit:
 			game.Save()
+			game.pregenerator = nil
+			game.world.Unload()
 			game.playerStack.Pop()
 			game.player = game.playerStack.Top()
 			// reload the world
-			game.world = world.Load(game.player.SelectedWorld.BaseUUID, game.player.SelectedWorld.UUID)
+			previousWorld, err := loadSelectedWorld(game.player.SelectedWorld)
+			if err != nil {
+				log.Panicf("failed to load the world - %v", err)
+			}
+			game.world = previousWorld
 			game.Save()
+		case event.FurnaceOpen:
+			scene_manager.ShowOverlay(&furnaceScene{furnace: ev.Args().(types.IFurnaceBlock)})
 		}
 	}
 }
@@ -262,19 +352,19 @@ func (game *Game) Update() {
 }
 
 func (game *Game) Draw(screen *ebiten.Image) {
-	game.world.Render(screen, game.player.X, game.player.Y, config.UIScaling)
+	game.worldRenderer.Render(screen, game.world, game.player.X, game.player.Y, config.UIScaling)
 
 	if !game.inventory.SelectedSlot().Empty {
-		screenPos := world.BlockToScreen(screen, types.Vec2f{X: game.player.X, Y: game.player.Y}, game.player.LookingAt(), config.UIScaling)
-		tex := assets.Texture("outline1").Texture()
+		screenPos := render.BlockToScreen(screen, types.Vec2f{X: game.player.X, Y: game.player.Y}, game.player.LookingAt(), config.UIScaling)
+		tex := textures.Texture("outline1")
 		opts := &ebiten.DrawImageOptions{}
 		opts.GeoM.Translate(screenPos.X, screenPos.Y)
 		opts.GeoM.Scale(config.UIScaling, config.UIScaling)
 		screen.DrawImage(tex, opts)
 	}
-	game.player.Render(screen, config.UIScaling, game.paused)
+	renderPlayer(screen, game.player, config.UIScaling, game.paused)
 
-	game.inventory.Render(screen)
+	renderInventory(screen, game.inventory)
 
 	ui.ImmediateDraw(screen,
 		ui.PositionSelf(ui.PositionTopRight, ui.Padding(0.5,
@@ -286,21 +376,35 @@ func (game *Game) Draw(screen *ebiten.Image) {
 			fmt.Sprintf(
 				heredoc.Doc(`
 					player pos:		%.2f, %.2f
+					depth:			%v (%v)
+					biome:			%v
 					world seed:		%v
 					UI scaling:		%v
 
 					FPS:			%.0f
 					TPS:			%.0f
 				`),
-				game.player.X, game.player.Y, game.world.Seed(), config.UIScaling, ebiten.ActualFPS(), ebiten.ActualTPS(),
+				game.player.X, game.player.Y,
+				game.world.Metadata().WorldType.Depth(), game.world.Metadata().WorldType,
+				game.world.Generator().BiomeAt(uint64(game.player.X), uint64(game.player.Y)),
+				game.world.Seed(), config.UIScaling, ebiten.ActualFPS(), ebiten.ActualTPS(),
 			),
 			0, 0, colors.C("black"),
 		)
 
+		if game.pregenerator != nil {
+			done, total := game.pregenerator.Progress()
+			font.RenderFont(screen,
+				fmt.Sprintf("pre-generating: %v/%v chunks", done, total),
+				0, float64(screen.Bounds().Dy())-16*config.UIScaling, colors.C("black"),
+			)
+		}
+
 	}
 }
 
 func (game *Game) Destroy() {
 	game.Save()
+	game.world.Unload()
 	log.Println("GameScene.Destroy() called")
 }
@@ -0,0 +1,133 @@
+/*
+	Drawing of the player and the inventory.
+	Packages player and inventory don't import ebiten, so that saves can be read without a graphics context.
+*/
+
+package game
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/3elDU/bamboo/colors"
+	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/font"
+	"github.com/3elDU/bamboo/game/inventory"
+	"github.com/3elDU/bamboo/game/player"
+	"github.com/3elDU/bamboo/textures"
+	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/ui"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func renderPlayer(screen *ebiten.Image, p *player.Player, scaling float64, paused bool) {
+	opts := &ebiten.DrawImageOptions{}
+	sw, sh := screen.Bounds().Dx(), screen.Bounds().Dy()
+	texture, frame := p.AnimationFrame()
+	tex := ebiten.NewImageFromImage(
+		textures.Texture(texture).SubImage(
+			image.Rect(frame*16, 0, frame*16+16, 32),
+		),
+	)
+
+	opts.GeoM.Reset()
+	opts.GeoM.Scale(scaling, scaling)
+	opts.GeoM.Translate(
+		float64(sw)/2-8*scaling,
+		float64(sh)/2-20*scaling,
+	)
+	screen.DrawImage(tex, opts)
+
+	if !paused {
+		p.NextAnimationFrame()
+	}
+}
+
+// Returns a position of inventory slot on the screen
+func slotToScreenCoords(screen *ebiten.Image, slot int) types.Vec2f {
+	w, h := textures.ScaledSize("inventory")
+	sw, sh := screen.Bounds().Dx(), screen.Bounds().Dy()
+	return types.Vec2f{
+		X: (float64(sw)/2 - float64(w)/2) + 4*float64(config.UIScaling) + (20 * float64(slot) * config.UIScaling),
+		Y: (float64(sh) - h) + 3*float64(config.UIScaling),
+	}
+}
+
+func mouseOverSlot(screen *ebiten.Image, slot int) bool {
+	itemTexPos := slotToScreenCoords(screen, slot)
+	cx, cy := ebiten.CursorPosition()
+	return float64(cx) > itemTexPos.X && float64(cy) > itemTexPos.Y && float64(cx) < itemTexPos.X+16*config.UIScaling && float64(cy) < itemTexPos.Y+16*config.UIScaling
+}
+
+func renderInventory(screen *ebiten.Image, inv *inventory.Inventory) {
+	inventoryDrawOpts := &ebiten.DrawImageOptions{}
+
+	w, h := textures.ScaledSize("inventory")
+	sw, sh := screen.Bounds().Dx(), screen.Bounds().Dy()
+
+	// position of inventory texture on the screen
+	ix := float64(sw)/2 - float64(w)/2 // horizontally centered
+	iy := float64(sh) - h              // bottom of the screen
+
+	inventoryDrawOpts.GeoM.Scale(config.UIScaling, config.UIScaling)
+	inventoryDrawOpts.GeoM.Translate(ix, iy)
+
+	screen.DrawImage(textures.Texture("inventory"), inventoryDrawOpts)
+
+	for i, slot := range inv.Slots {
+		if slot.Empty {
+			continue
+		}
+
+		itemTex := textures.Texture(slot.Item.TextureName())
+		itemTexOpts := &ebiten.DrawImageOptions{}
+
+		itemTexPos := slotToScreenCoords(screen, i)
+
+		itemTexOpts.GeoM.Scale(config.UIScaling, config.UIScaling)
+		itemTexOpts.GeoM.Translate(itemTexPos.X, itemTexPos.Y)
+
+		screen.DrawImage(itemTex, itemTexOpts)
+
+		// Render label with item amount only if there is more than 1 of that item
+		if slot.Quantity > 1 {
+			font.RenderFont(screen, fmt.Sprintf("%v", slot.Quantity), itemTexPos.X, itemTexPos.Y, colors.C("white"))
+		}
+	}
+
+	// Draw an outline around the selected slot
+	selectedSlotTex := textures.Texture("selected_slot")
+	selectedSlotTexOpts := &ebiten.DrawImageOptions{}
+	selectedSlotTexOpts.GeoM.Scale(config.UIScaling, config.UIScaling)
+	selectedSlotTexOpts.GeoM.Translate(
+		ix+config.UIScaling+(20*float64(inv.SelectedSlotIndex())*config.UIScaling),
+		iy,
+	)
+	screen.DrawImage(selectedSlotTex, selectedSlotTexOpts)
+
+	// Draw inventory badges on top of everything, so they will be always visible
+	inventoryBadgesTex := textures.Texture("inventory_badges")
+	screen.DrawImage(inventoryBadgesTex, inventoryDrawOpts)
+
+	// Check if cursor hovers over one of the items in inventory, and render item's tooltip
+	for i := 0; i < inv.Length(); i++ {
+		slot := inv.At(i)
+		if slot.Empty {
+			continue
+		}
+
+		if mouseOverSlot(screen, i) {
+			item := slot.Item
+
+			var tooltipText string
+			if item.Description() == "" {
+				tooltipText = item.Name()
+			} else {
+				tooltipText = fmt.Sprintf("%v\n------\n%v", item.Name(), item.Description())
+			}
+
+			cx, cy := ebiten.CursorPosition()
+			ui.DrawTextTooltip(screen, cx, cy, ui.TopRight, tooltipText)
+		}
+	}
+}
@@ -1,15 +1,8 @@
 package inventory
 
 import (
-	"fmt"
-
-	"github.com/3elDU/bamboo/assets"
-	"github.com/3elDU/bamboo/colors"
 	"github.com/3elDU/bamboo/config"
-	"github.com/3elDU/bamboo/font"
 	"github.com/3elDU/bamboo/types"
-	"github.com/3elDU/bamboo/ui"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 const Size = 5
@@ -87,9 +80,16 @@ func (inv *Inventory) RemoveItemByType(itemType types.ItemType, amount int) bool
 	return false
 }
 
+// Items are added to a copy of the inventory, so items, that only fit one at a time, are rejected
 func (inv *Inventory) CanAddItems(items ...types.ItemSlot) bool {
+	simulated := &Inventory{}
+	for i, slot := range inv.Slots {
+		copied := *slot
+		simulated.Slots[i] = &copied
+	}
+
 	for _, item := range items {
-		if !inv.CanAddItem(item) {
+		if !simulated.AddItem(item) {
 			return false
 		}
 	}
@@ -105,7 +105,7 @@ func (inv *Inventory) CanAddItem(item types.ItemSlot) bool {
 		if slot.Empty {
 			return true
 		}
-		if slot.Item.Type() == item.Item.Type() && slot.Quantity+item.Quantity <= config.SlotSize {
+		if slot.Item.Type() == item.Item.Type() && slot.Item.Stackable() && slot.Quantity+item.Quantity <= config.SlotSize {
 			return true
 		}
 	}
@@ -166,94 +166,3 @@ func (inv *Inventory) SelectedSlot() *types.ItemSlot {
 func (inv *Inventory) SelectedSlotIndex() int {
 	return inv.selectedSlot
 }
-
-// Returns a position of inventory slot on the screen
-func (inv *Inventory) SlotToScreenCoords(screen *ebiten.Image, slot int) types.Vec2f {
-	inventoryTexture := assets.Texture("inventory")
-	w, h := inventoryTexture.ScaledSize()
-	sw, sh := screen.Bounds().Dx(), screen.Bounds().Dy()
-	return types.Vec2f{
-		X: (float64(sw)/2 - float64(w)/2) + 4*float64(config.UIScaling) + (20 * float64(slot) * config.UIScaling),
-		Y: (float64(sh) - h) + 3*float64(config.UIScaling),
-	}
-}
-
-func (inv *Inventory) MouseOverSlot(screen *ebiten.Image, slot int) bool {
-	itemTexPos := inv.SlotToScreenCoords(screen, slot)
-	cx, cy := ebiten.CursorPosition()
-	return float64(cx) > itemTexPos.X && float64(cy) > itemTexPos.Y && float64(cx) < itemTexPos.X+16*config.UIScaling && float64(cy) < itemTexPos.Y+16*config.UIScaling
-}
-
-func (inv *Inventory) Render(screen *ebiten.Image) {
-	inventoryTexture := assets.Texture("inventory")
-	inventoryDrawOpts := &ebiten.DrawImageOptions{}
-
-	w, h := inventoryTexture.ScaledSize()
-	sw, sh := screen.Bounds().Dx(), screen.Bounds().Dy()
-
-	// position of inventory texture on the screen
-	ix := float64(sw)/2 - float64(w)/2 // horizontally centered
-	iy := float64(sh) - h              // bottom of the screen
-
-	inventoryDrawOpts.GeoM.Scale(config.UIScaling, config.UIScaling)
-	inventoryDrawOpts.GeoM.Translate(ix, iy)
-
-	screen.DrawImage(inventoryTexture.Texture(), inventoryDrawOpts)
-
-	for i, slot := range inv.Slots {
-		if slot.Empty {
-			continue
-		}
-
-		itemTex := slot.Item.Texture()
-		itemTexOpts := &ebiten.DrawImageOptions{}
-
-		itemTexPos := inv.SlotToScreenCoords(screen, i)
-
-		itemTexOpts.GeoM.Scale(config.UIScaling, config.UIScaling)
-		itemTexOpts.GeoM.Translate(itemTexPos.X, itemTexPos.Y)
-
-		screen.DrawImage(itemTex, itemTexOpts)
-
-		// Render label with item amount only if there is more than 1 of that item
-		if slot.Quantity > 1 {
-			font.RenderFont(screen, fmt.Sprintf("%v", slot.Quantity), itemTexPos.X, itemTexPos.Y, colors.C("white"))
-		}
-	}
-
-	// Draw an outline around the selected slot
-	selectedSlotTex := assets.Texture("selected_slot").Texture()
-	selectedSlotTexOpts := &ebiten.DrawImageOptions{}
-	selectedSlotTexOpts.GeoM.Scale(config.UIScaling, config.UIScaling)
-	selectedSlotTexOpts.GeoM.Translate(
-		ix+config.UIScaling+(20*float64(inv.selectedSlot)*config.UIScaling),
-		iy,
-	)
-	screen.DrawImage(selectedSlotTex, selectedSlotTexOpts)
-
-	// Draw inventory badges on top of everything, so they will be always visible
-	inventoryBadgesTex := assets.Texture("inventory_badges").Texture()
-	screen.DrawImage(inventoryBadgesTex, inventoryDrawOpts)
-
-	// Check if cursor hovers over one of the items in inventory, and render item's tooltip
-	for i := 0; i < inv.Length(); i++ {
-		slot := inv.At(i)
-		if slot.Empty {
-			continue
-		}
-
-		if inv.MouseOverSlot(screen, i) {
-			item := slot.Item
-
-			var tooltipText string
-			if item.Description() == "" {
-				tooltipText = item.Name()
-			} else {
-				tooltipText = fmt.Sprintf("%v\n------\n%v", item.Name(), item.Description())
-			}
-
-			cx, cy := ebiten.CursorPosition()
-			ui.DrawTextTooltip(screen, cx, cy, ui.TopRight, tooltipText)
-		}
-	}
-}
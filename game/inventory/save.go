@@ -3,68 +3,147 @@ package inventory
 import (
 	"bytes"
 	"encoding/gob"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 
 	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/util"
+	"github.com/3elDU/bamboo/world"
 	"github.com/google/uuid"
 )
 
-func LoadInventory(baseUUID uuid.UUID) *Inventory {
-	path := filepath.Join(config.WorldSaveDirectory, baseUUID.String(), config.InventoryFile)
+// Inventory as it is stored on the disk
+type savedInventory struct {
+	// Save format version the inventory was saved with
+	Version int
+	Slots   []types.SavedSlot
+}
+
+func inventoryPath(baseUUID uuid.UUID) string {
+	return filepath.Join(config.WorldSaveDirectory, baseUUID.String(), config.InventoryFile)
+}
+
+func decodeInventory(data []byte) (savedInventory, error) {
+	loadedInventory := savedInventory{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&loadedInventory); err != nil {
+		// inventories saved before the versioning was introduced are a plain list of slots
+		loadedInventory.Slots = make([]types.SavedSlot, Size)
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&loadedInventory.Slots); err != nil {
+			return loadedInventory, err
+		}
+	}
+	return loadedInventory, nil
+}
+
+// ReadSavedSlots returns inventory slots as they are stored on the disk,
+// with item states migrated to the current save format version
+func ReadSavedSlots(baseUUID uuid.UUID) ([]types.SavedSlot, error) {
+	data, err := os.ReadFile(inventoryPath(baseUUID))
+	if err != nil {
+		return nil, err
+	}
+
+	loadedInventory, err := decodeInventory(data)
+	if err != nil {
+		return nil, err
+	}
+	for i := range loadedInventory.Slots {
+		loadedInventory.Slots[i].Migrate(loadedInventory.Version)
+	}
+	return loadedInventory.Slots, nil
+}
 
-	data, err := os.ReadFile(path)
+// LoadInventory returns an empty inventory, if the inventory file doesn't exist or is corrupted.
+// Corrupted file is moved to the quarantine.
+func LoadInventory(baseUUID uuid.UUID) (*Inventory, error) {
+	data, err := os.ReadFile(inventoryPath(baseUUID))
 	if err != nil {
 		log.Printf("failed to read inventory save file: %v", err)
-		return NewInventory()
+		return NewInventory(), nil
 	}
 
-	loadedInventory := make([]types.SavedSlot, Size)
-	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&loadedInventory); err != nil {
+	loadedInventory, err := decodeInventory(data)
+	if err != nil {
 		log.Printf("failed to decode inventory: %v", err)
-		return NewInventory()
+		world.QuarantineFile(baseUUID, config.InventoryFile, data)
+		return NewInventory(), nil
+	}
+	if loadedInventory.Version > config.SaveFormatVersion {
+		return nil, fmt.Errorf("inventory was %w %v", world.ErrNewerSaveVersion, loadedInventory.Version)
 	}
 
 	inventory := NewInventory()
-	for i := 0; i < Size; i++ {
-		if loadedInventory[i].Empty {
+	for i := 0; i < Size && i < len(loadedInventory.Slots); i++ {
+		savedSlot := loadedInventory.Slots[i]
+		if savedSlot.Empty {
 			continue
 		}
-		item := types.NewItem(loadedInventory[i].ItemType)
-		item.LoadState(loadedInventory[i].State)
-		inventory.Slots[i] = &types.ItemSlot{
-			Empty:    false,
-			Quantity: loadedInventory[i].Quantity,
-			Item:     item,
-		}
+		savedSlot.Migrate(loadedInventory.Version)
+		slot := savedSlot.Load()
+		inventory.Slots[i] = &slot
+	}
+	if loadedInventory.Version < 2 {
+		inventory.splitToolStacks(loadedInventory)
 	}
 
-	return inventory
+	return inventory, nil
 }
 
-func (inv *Inventory) Save(metadata types.Save) {
-	path := filepath.Join(config.WorldSaveDirectory, metadata.BaseUUID.String(), config.InventoryFile)
+// Tools were stackable before save format version 2, when they got durability.
+// Each tool from such stack is moved to its own empty slot, if there is one.
+func (inv *Inventory) splitToolStacks(loadedInventory savedInventory) {
+	for i := 0; i < Size && i < len(loadedInventory.Slots); i++ {
+		slot := inv.Slots[i]
+		for !slot.Empty && !slot.Item.Stackable() && slot.Quantity > 1 {
+			empty := -1
+			for j, other := range inv.Slots {
+				if other.Empty {
+					empty = j
+					break
+				}
+			}
+			if empty == -1 {
+				log.Printf("no empty slot to split the stack of %v tools, leaving %v in one slot", slot.Item.Name(), slot.Quantity)
+				return
+			}
 
-	file, err := os.Create(path)
-	if err != nil {
-		log.Panicf("failed to open inventory file for saving: %v", err)
+			// each tool gets its own item, so their durability is tracked separately
+			savedSlot := loadedInventory.Slots[i]
+			savedSlot.Migrate(loadedInventory.Version)
+			split := savedSlot.Load()
+			split.Quantity = 1
+			inv.Slots[empty] = &split
+			slot.Quantity--
+		}
 	}
-	defer file.Close()
+}
 
-	savedInventory := make([]types.SavedSlot, Size)
+// Save adds the inventory to the given writer
+func (inv *Inventory) Save(files *util.AtomicWriter, metadata types.Save) {
+	path := inventoryPath(metadata.BaseUUID)
+
+	saved := savedInventory{
+		Version: config.SaveFormatVersion,
+		Slots:   make([]types.SavedSlot, Size),
+	}
 	for i := 0; i < Size; i++ {
 		if inv.Slots[i].Empty {
-			savedInventory[i] = types.SavedSlot{
+			saved.Slots[i] = types.SavedSlot{
 				Empty: true,
 			}
 		} else {
-			savedInventory[i] = inv.Slots[i].Save()
+			saved.Slots[i] = inv.Slots[i].Save()
 		}
 	}
 
-	if err := gob.NewEncoder(file).Encode(savedInventory); err != nil {
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(saved); err != nil {
 		log.Panicf("failed to encode inventory: %v", err)
 	}
+	if err := files.Add(path, buf.Bytes()); err != nil {
+		log.Panicf("failed to open inventory file for saving: %v", err)
+	}
 }
@@ -0,0 +1,44 @@
+/*
+	Player animation. The player itself is drawn by the game
+*/
+
+package player
+
+import (
+	"time"
+)
+
+var textureMap = map[MovementDirection]string{
+	Left:  "player_left",
+	Right: "player_right",
+	Up:    "player_up",
+	Down:  "player_down",
+}
+
+// Returns the texture with animation frames for the current movement direction,
+// and the current frame in it
+func (player *Player) AnimationFrame() (texture string, frame int) {
+	return textureMap[player.MovementDirection], int(player.animationFrame)
+}
+
+// Called each frame, while the game isn't paused
+func (player *Player) NextAnimationFrame() {
+	// run at precisely 5 fps
+	if time.Since(player.lastFrameChange).Seconds() < 0.2 {
+		return
+	}
+
+	// if the player is standing still, reset the frame to 0
+	if player.speed() < 0.01 {
+		player.animationFrame = 0
+		return
+	}
+
+	if player.animationFrame >= 3 {
+		player.animationFrame = 0
+	} else {
+		player.animationFrame++
+	}
+
+	player.lastFrameChange = time.Now()
+}
@@ -1,6 +1,7 @@
 package player
 
 import (
+	"context"
 	"log"
 	"math/rand"
 	"time"
@@ -57,7 +58,7 @@ func (mvec MovementVector) ToFloat() (vx, vy float64) {
 
 func isValidSpawnpoint(blockType types.BlockType) bool {
 	validBlocks := []types.BlockType{
-		types.SandBlock, types.GrassBlock, types.ShortGrassBlock, types.TallGrassBlock, types.FlowersBlock, types.RedMushroomBlock, types.WhiteMushroomBlock,
+		types.SandBlock, types.GrassBlock, types.SnowBlock, types.ShortGrassBlock, types.TallGrassBlock, types.FlowersBlock, types.RedMushroomBlock, types.WhiteMushroomBlock,
 		types.CaveFloorBlock,
 	}
 
@@ -69,34 +70,51 @@ func isValidSpawnpoint(blockType types.BlockType) bool {
 	return false
 }
 
-// Creates a new player, picking a valid spawn point
-func NewPlayer(w types.World) *Player {
+// Spawn point search gives up after this many random points,
+// e.g. when the preset makes the spawn area all water
+const MaxSpawnAttempts = 10000
+
+// Picks a valid spawn point in the world with that metadata.
+// The same point is picked every time, so it can be previewed before the world is created
+func FindSpawnPoint(metadata types.Save, generator types.WorldGenerator) (uint64, uint64) {
+	spawn, found := SearchSpawnPoint(context.Background(), metadata, func(x, y uint64) types.BlockType {
+		// create a new chunk so that we don't overwrite chunks in the world
+		c := world.NewChunk(x/16, y/16)
+		generator.GenerateImmediately(c)
+		return c.At(uint(x%16), uint(y%16)).Type()
+	})
+	if !found {
+		areaMin, areaMax := metadata.Shape.SpawnArea(metadata.Size)
+		spawn = types.Vec2u{X: (areaMin.X + areaMax.X) / 2, Y: (areaMin.Y + areaMax.Y) / 2}
+		log.Printf("no valid spawn point after %v attempts, spawning at the center (%v, %v)", MaxSpawnAttempts, spawn.X, spawn.Y)
+	}
+	return spawn.X, spawn.Y
+}
+
+// SearchSpawnPoint tries random points in the spawn area, until blockTypeAt returns a block, that the player can spawn on.
+// Returns false, if there is no such block after MaxSpawnAttempts, or if the context is cancelled.
+func SearchSpawnPoint(ctx context.Context, metadata types.Save, blockTypeAt func(x, y uint64) types.BlockType) (types.Vec2u, bool) {
 	// use the same seed for reproducible spawnpoint generation
 	rng := rand.New(rand.NewSource(1))
 
-	x, y := 0, 0
-	worldWidth := int(w.Size().X)
-	worldHeight := int(w.Size().Y)
-	it := 1
-	for {
-		// Pick X and Y coordinates from 1/4 of the world to 3/4 of the world
-		// E.g. if the world is 1024 blocks in size, coordinates would be in range from 256 to 768
-		x = rng.Intn(worldWidth/4*3-worldWidth/4) + worldWidth/4
-		y = rng.Intn(worldHeight/4*3-worldHeight/4) + worldHeight/4
+	// E.g. if the world is a single island 1024 blocks in size, coordinates would be in range from 256 to 768
+	areaMin, areaMax := metadata.Shape.SpawnArea(metadata.Size)
+	for it := 1; it <= MaxSpawnAttempts && ctx.Err() == nil; it++ {
+		x := uint64(rng.Intn(int(areaMax.X-areaMin.X))) + areaMin.X
+		y := uint64(rng.Intn(int(areaMax.Y-areaMin.Y))) + areaMin.Y
 
-		// create a new chunk so that we don't overwrite chunks in the world
-		c := world.NewChunk(uint64(x)/16, uint64(y)/16)
-		w.Generator().GenerateImmediately(c)
-		blockType := c.At(uint(x%16), uint(y%16)).Type()
-
-		if isValidSpawnpoint(blockType) {
-			break
+		if isValidSpawnpoint(blockTypeAt(x, y)) {
+			log.Printf("picked spawn point (%v, %v), took %v iterations", x, y, it)
+			return types.Vec2u{X: x, Y: y}, true
 		}
-
-		it++
 	}
-	log.Printf("picked spawn point (%v, %v), took %v iterations", x, y, it)
-	w.SetPlayerSpawnPoint(uint64(x), uint64(y))
+	return types.Vec2u{}, false
+}
+
+// Creates a new player, picking a valid spawn point
+func NewPlayer(w types.World) *Player {
+	x, y := FindSpawnPoint(w.Metadata(), w.Generator())
+	w.SetPlayerSpawnPoint(x, y)
 
 	return &Player{X: float64(x), Y: float64(y), SelectedWorld: w.Metadata()}
 }
@@ -1,9 +1,13 @@
 package player
 
 import (
+	"bytes"
 	"encoding/gob"
+	"fmt"
 	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/util"
+	"github.com/3elDU/bamboo/world"
 	"github.com/google/uuid"
 	"log"
 	"os"
@@ -15,12 +19,15 @@ import (
 // When player enters a new world, new player state is pushed onto the stack, and it becomes the current state
 // When player goes back, we pop last element in the stack, going back to previous player state
 type Stack struct {
-	Stack []*Player
+	// Save format version the stack was saved with
+	Version int
+	Stack   []*Player
 }
 
 func NewPlayerStack() *Stack {
 	return &Stack{
-		Stack: make([]*Player, 0),
+		Version: config.SaveFormatVersion,
+		Stack:   make([]*Player, 0),
 	}
 }
 
@@ -42,37 +49,52 @@ func (stack *Stack) Pop() *Player {
 	return top
 }
 
-func LoadPlayerStack(baseUUID uuid.UUID) *Stack {
+// ReadPlayerStack decodes the player stack from the disk, without any recovery
+func ReadPlayerStack(baseUUID uuid.UUID) (*Stack, error) {
+	data, err := os.ReadFile(filepath.Join(config.WorldSaveDirectory, baseUUID.String(), config.PlayerInfoFile))
+	if err != nil {
+		return nil, err
+	}
+
+	stack := new(Stack)
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(stack)
+	return stack, err
+}
+
+// LoadPlayerStack returns an empty stack, if the player file doesn't exist or is corrupted.
+// Corrupted file is moved to the quarantine.
+func LoadPlayerStack(baseUUID uuid.UUID) (*Stack, error) {
 	saveDir := filepath.Join(config.WorldSaveDirectory, baseUUID.String())
 
-	f, err := os.Open(filepath.Join(saveDir, config.PlayerInfoFile))
+	data, err := os.ReadFile(filepath.Join(saveDir, config.PlayerInfoFile))
 	if err != nil {
 		// if file does not exist, create a new stack
-		return NewPlayerStack()
+		return NewPlayerStack(), nil
 	}
 
 	stack := new(Stack)
-	if err := gob.NewDecoder(f).Decode(stack); err != nil {
-		log.Panicf("LoadPlayerStack() - failed to decode metadata - %v", err)
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(stack); err != nil {
+		log.Printf("LoadPlayerStack() - failed to decode metadata - %v", err)
+		world.QuarantineFile(baseUUID, config.PlayerInfoFile, data)
+		return NewPlayerStack(), nil
 	}
+	if stack.Version > config.SaveFormatVersion {
+		return nil, fmt.Errorf("LoadPlayerStack() - player stack was %w %v", world.ErrNewerSaveVersion, stack.Version)
+	}
+	stack.Version = config.SaveFormatVersion
 
-	return stack
+	return stack, nil
 }
 
-func (stack *Stack) Save(metadata types.Save) {
+// Save adds the player stack to the given writer
+func (stack *Stack) Save(files *util.AtomicWriter, metadata types.Save) {
 	saveDir := filepath.Join(config.WorldSaveDirectory, metadata.BaseUUID.String())
 
-	// make a save directory, if it doesn't exist yet
-	os.Mkdir(saveDir, os.ModePerm)
-
-	// open world metadata file
-	f, err := os.Create(filepath.Join(saveDir, config.PlayerInfoFile))
-	if err != nil {
-		log.Panicf("failed to create player metadata file")
-	}
-	defer f.Close()
-
-	if err := gob.NewEncoder(f).Encode(stack); err != nil {
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(stack); err != nil {
 		log.Panicf("failed to write player metadata")
 	}
+	if err := files.Add(filepath.Join(saveDir, config.PlayerInfoFile), buf.Bytes()); err != nil {
+		log.Panicf("failed to create player metadata file - %v", err)
+	}
 }
@@ -6,6 +6,7 @@ require (
 	github.com/MakeNowJust/heredoc v1.0.0
 	github.com/aquilax/go-perlin v1.1.0
 	github.com/hajimehoshi/ebiten/v2 v2.5.3
+	github.com/klauspost/compress v1.16.5
 	github.com/pkg/profile v1.7.0
 	github.com/teacat/noire v1.1.0
 	golang.org/x/exp v0.0.0-20230425010034-47ecfdc1ba53
@@ -33,6 +33,7 @@ func (i *baseItem) State() interface{} {
 }
 
 func (i *baseItem) LoadState(s interface{}) {
-	state := s.(BaseItemState)
-	i.id = state.Type
+	// The item type is set by the constructor.
+	// Type saved in the state is a numeric ID, which may belong to a different item by now
+	_ = s.(BaseItemState)
 }
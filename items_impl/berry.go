@@ -1,16 +1,17 @@
 package items_impl
 
 import (
-	"encoding/gob"
-
 	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func init() {
-	types.NewBerryItem = NewBerryItem
-	gob.Register(BerryItemState{})
+	types.RegisterItem(types.ItemDefinition{
+		Type:  types.BerryItem,
+		Key:   "bamboo:berry",
+		New:   NewBerryItem,
+		State: BerryItemState{},
+	})
 }
 
 type BerryItemState struct {
@@ -39,8 +40,8 @@ func (berry *BerryItem) Description() string {
 	return "Berry tasty!"
 }
 
-func (berry *BerryItem) Texture() *ebiten.Image {
-	return berry.texture.Texture()
+func (berry *BerryItem) TextureName() string {
+	return berry.texture.Name()
 }
 
 func (berry *BerryItem) State() interface{} {
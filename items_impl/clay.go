@@ -1,15 +1,19 @@
 package items_impl
 
 import (
-	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func init() {
-	types.NewClayItem = NewClayItem
+	types.RegisterItem(types.ItemDefinition{
+		Type: types.ClayItem,
+		Key:  "bamboo:clay",
+		New:  NewClayItem,
+	})
 }
 
+// Clay isn't in assets/definitions like other materials, because it can be placed back on sand,
+// and data items can't do anything when used
 type ClayItem struct {
 	baseItem
 }
@@ -29,8 +33,8 @@ func (item *ClayItem) Description() string {
 	return ""
 }
 
-func (item *ClayItem) Texture() *ebiten.Image {
-	return assets.Texture("clay").Texture()
+func (item *ClayItem) TextureName() string {
+	return "clay"
 }
 
 func (item *ClayItem) ToolFamily() types.ToolFamily {
@@ -44,7 +48,7 @@ func (item *ClayItem) UseTool(pos types.Vec2u) {
 	if types.GetCurrentWorld().BlockAt(pos.X, pos.Y).Type() == types.SandBlock {
 		types.GetCurrentWorld().SetBlock(
 			pos.X, pos.Y,
-			types.NewSandWithClayBlock(),
+			types.NewBlock(types.SandWithClayBlock),
 		)
 		types.GetPlayerInventory().RemoveItemByType(item.Type(), 1)
 	}
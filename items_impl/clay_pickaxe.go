@@ -1,22 +1,28 @@
 package items_impl
 
 import (
-	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func init() {
-	types.NewClayPickaxeItem = NewClayPickaxeItem
+	types.RegisterItem(types.ItemDefinition{
+		Type:  types.ClayPickaxeItem,
+		Key:   "bamboo:clay_pickaxe",
+		New:   NewClayPickaxeItem,
+		State: DurableItemState{},
+	})
+	registerDurabilityMigration(types.ClayPickaxeItem, clayPickaxeDurability)
 }
 
+const clayPickaxeDurability = 32
+
 type ClayPickaxeItem struct {
-	baseItem
+	durableItem
 }
 
 func NewClayPickaxeItem() types.Item {
 	return &ClayPickaxeItem{
-		baseItem{id: types.ClayPickaxeItem},
+		durableItem: newDurableItem(types.ClayPickaxeItem, clayPickaxeDurability),
 	}
 }
 
@@ -26,8 +32,8 @@ func (pickaxe *ClayPickaxeItem) Name() string {
 func (pickaxe *ClayPickaxeItem) Description() string {
 	return ""
 }
-func (pickaxe *ClayPickaxeItem) Texture() *ebiten.Image {
-	return assets.Texture("clay_pickaxe").Texture()
+func (pickaxe *ClayPickaxeItem) TextureName() string {
+	return "clay_pickaxe"
 }
 
 func (pickaxe *ClayPickaxeItem) ToolFamily() types.ToolFamily {
@@ -1,31 +1,31 @@
 package items_impl
 
 import (
-	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func init() {
-	types.NewClayShovelItem = NewClayShovelItem
+	types.RegisterItem(types.ItemDefinition{
+		Type:  types.ClayShovelItem,
+		Key:   "bamboo:clay_shovel",
+		New:   NewClayShovelItem,
+		State: DurableItemState{},
+	})
+	registerDurabilityMigration(types.ClayShovelItem, clayShovelDurability)
 }
 
+const clayShovelDurability = 32
+
 type ClayShovelItem struct {
-	baseItem
+	durableItem
 }
 
 func NewClayShovelItem() types.Item {
 	return &ClayShovelItem{
-		baseItem: baseItem{
-			id: types.ClayShovelItem,
-		},
+		durableItem: newDurableItem(types.ClayShovelItem, clayShovelDurability),
 	}
 }
 
-func (shovel *ClayShovelItem) Stackable() bool {
-	return false
-}
-
 func (shovel *ClayShovelItem) Name() string {
 	return "Clay shovel"
 }
@@ -33,8 +33,8 @@ func (shovel *ClayShovelItem) Description() string {
 	return ""
 }
 
-func (shovel *ClayShovelItem) Texture() *ebiten.Image {
-	return assets.Texture("clay_shovel").Texture()
+func (shovel *ClayShovelItem) TextureName() string {
+	return "clay_shovel"
 }
 
 func (shovel *ClayShovelItem) ToolFamily() types.ToolFamily {
@@ -44,5 +44,5 @@ func (shovel *ClayShovelItem) ToolStrength() types.ToolStrength {
 	return types.ToolStrengthClay
 }
 func (shovel *ClayShovelItem) UseTool(pos types.Vec2u) {
-	types.GetCurrentWorld().SetBlock(pos.X, pos.Y, types.NewPitBlock())
+	types.GetCurrentWorld().SetBlock(pos.X, pos.Y, types.NewBlock(types.PitBlock))
 }
@@ -0,0 +1,47 @@
+package items_impl
+
+import (
+	"github.com/3elDU/bamboo/types"
+)
+
+func init() {
+	types.RegisterItem(types.ItemDefinition{
+		Type:  types.CopperPickaxeItem,
+		Key:   "bamboo:copper_pickaxe",
+		New:   NewCopperPickaxeItem,
+		State: DurableItemState{},
+	})
+	registerDurabilityMigration(types.CopperPickaxeItem, copperPickaxeDurability)
+}
+
+const copperPickaxeDurability = 96
+
+type CopperPickaxeItem struct {
+	durableItem
+}
+
+func NewCopperPickaxeItem() types.Item {
+	return &CopperPickaxeItem{
+		durableItem: newDurableItem(types.CopperPickaxeItem, copperPickaxeDurability),
+	}
+}
+
+func (pickaxe *CopperPickaxeItem) Name() string {
+	return "Copper pickaxe"
+}
+func (pickaxe *CopperPickaxeItem) Description() string {
+	return ""
+}
+func (pickaxe *CopperPickaxeItem) TextureName() string {
+	return "copper_pickaxe"
+}
+
+func (pickaxe *CopperPickaxeItem) ToolFamily() types.ToolFamily {
+	return types.ToolFamilyPickaxe
+}
+func (pickaxe *CopperPickaxeItem) ToolStrength() types.ToolStrength {
+	return types.ToolStrengthCopper
+}
+func (pickaxe *CopperPickaxeItem) UseTool(_ types.Vec2u) {
+
+}
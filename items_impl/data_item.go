@@ -0,0 +1,183 @@
+/*
+	Items described in JSON files in assets/definitions/items.
+	Materials, fuel and ores, that don't do anything when used, don't need any Go code.
+*/
+
+package items_impl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/3elDU/bamboo/assets"
+	"github.com/3elDU/bamboo/types"
+)
+
+func init() {
+	for _, file := range assets.Definitions("items") {
+		data, err := parseItemData(file.Data)
+		if err != nil {
+			log.Panicf("invalid item definition %v - %v", file.Path, err)
+		}
+		data.register()
+	}
+}
+
+type itemSmelting struct {
+	Energy float64 `json:"energy"`
+	Result string  `json:"result"`
+}
+
+// Item definition, as it is written in the JSON file
+type itemData struct {
+	Key string `json:"key"`
+	// Name of the type in types.ItemType enum.
+	// Only needed for items, that Go code refers to, others get their ID automatically.
+	Type string `json:"type"`
+
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Texture     string `json:"texture"`
+	Stackable   bool   `json:"stackable"`
+
+	// Optional, the item can be used as fuel, if it is set
+	BurningEnergy float64 `json:"burning_energy"`
+	// Optional, the item can be smelted in a furnace, if it is set
+	Smelting *itemSmelting `json:"smelting"`
+
+	itemType types.ItemType
+}
+
+func parseItemData(file []byte) (*itemData, error) {
+	data := &itemData{
+		Stackable: true,
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(file))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(data); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case data.Key == "":
+		return nil, fmt.Errorf("item has no key")
+	case data.Name == "":
+		return nil, fmt.Errorf("item has no name")
+	case !assets.TextureExists(data.Texture):
+		return nil, fmt.Errorf("texture %q doesn't exist", data.Texture)
+	case data.BurningEnergy < 0:
+		return nil, fmt.Errorf("burning energy can't be negative")
+	case data.Smelting != nil && data.Smelting.Energy <= 0:
+		return nil, fmt.Errorf("smelting energy must be positive")
+	}
+
+	if data.Type == "" {
+		data.itemType = types.AllocateItemType()
+	} else {
+		itemType, exists := types.ItemTypeByName(data.Type)
+		if !exists {
+			return nil, fmt.Errorf("unknown item type %q", data.Type)
+		}
+		data.itemType = itemType
+	}
+	return data, nil
+}
+
+func (data *itemData) register() {
+	types.RegisterItem(types.ItemDefinition{
+		Type: data.itemType,
+		Key:  data.Key,
+		New:  data.newItem,
+	})
+
+	if data.Smelting != nil {
+		// the result may be registered after this item
+		types.AddRegistryCheck(func() error {
+			if _, exists := types.ItemTypeByKey(data.Smelting.Result); !exists {
+				return fmt.Errorf("item %q smelts into unknown item %q", data.Key, data.Smelting.Result)
+			}
+			return nil
+		})
+	}
+}
+
+// Only items, that can actually be burned or smelted, implement the interfaces for that,
+// because the furnace checks for them with a type assertion
+func (data *itemData) newItem() types.Item {
+	item := dataItem{
+		baseItem: baseItem{id: data.itemType},
+		data:     data,
+	}
+
+	burnable := data.BurningEnergy > 0
+	smeltable := data.Smelting != nil
+	switch {
+	case burnable && smeltable:
+		return &burnableSmeltableDataItem{dataItem: item}
+	case burnable:
+		return &burnableDataItem{dataItem: item}
+	case smeltable:
+		return &smeltableDataItem{dataItem: item}
+	}
+	return &item
+}
+
+type dataItem struct {
+	baseItem
+	data *itemData
+}
+
+func (item *dataItem) Name() string {
+	return item.data.Name
+}
+func (item *dataItem) Description() string {
+	return item.data.Description
+}
+func (item *dataItem) TextureName() string {
+	return item.data.Texture
+}
+func (item *dataItem) Stackable() bool {
+	return item.data.Stackable
+}
+
+// Shared by the variants below, dataItem itself must not implement the interfaces
+func (data *itemData) smelt() types.Item {
+	result, _ := types.NewItemByKey(data.Smelting.Result)
+	return result
+}
+
+type burnableDataItem struct {
+	dataItem
+}
+
+func (item *burnableDataItem) BurningEnergy() float64 {
+	return item.data.BurningEnergy
+}
+
+type smeltableDataItem struct {
+	dataItem
+}
+
+func (item *smeltableDataItem) SmeltingEnergyRequired() float64 {
+	return item.data.Smelting.Energy
+}
+func (item *smeltableDataItem) Smelt() types.Item {
+	return item.data.smelt()
+}
+
+type burnableSmeltableDataItem struct {
+	dataItem
+}
+
+func (item *burnableSmeltableDataItem) BurningEnergy() float64 {
+	return item.data.BurningEnergy
+}
+func (item *burnableSmeltableDataItem) SmeltingEnergyRequired() float64 {
+	return item.data.Smelting.Energy
+}
+func (item *burnableSmeltableDataItem) Smelt() types.Item {
+	return item.data.smelt()
+}
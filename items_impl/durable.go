@@ -0,0 +1,67 @@
+package items_impl
+
+import "github.com/3elDU/bamboo/types"
+
+type DurableItemState struct {
+	BaseItemState
+	Durability int
+}
+
+// Base structure for tools, that wear out
+type durableItem struct {
+	baseItem
+	durability    int
+	maxDurability int
+}
+
+func newDurableItem(id types.ItemType, maxDurability int) durableItem {
+	return durableItem{
+		baseItem:      baseItem{id: id},
+		durability:    maxDurability,
+		maxDurability: maxDurability,
+	}
+}
+
+// Each tool wears out separately, so they can't be stacked
+func (i *durableItem) Stackable() bool {
+	return false
+}
+
+func (i *durableItem) Durability() int {
+	return i.durability
+}
+func (i *durableItem) MaxDurability() int {
+	return i.maxDurability
+}
+
+func (i *durableItem) Damage(amount int) bool {
+	i.durability -= amount
+	if i.durability <= 0 {
+		i.durability = 0
+		return true
+	}
+	return false
+}
+
+func (i *durableItem) State() interface{} {
+	return DurableItemState{
+		BaseItemState: i.baseItem.State().(BaseItemState),
+		Durability:    i.durability,
+	}
+}
+
+func (i *durableItem) LoadState(s interface{}) {
+	state := s.(DurableItemState)
+	i.baseItem.LoadState(state.BaseItemState)
+	i.durability = state.Durability
+}
+
+// Before save format version 2, tools didn't have durability, they are loaded as new
+func registerDurabilityMigration(itemType types.ItemType, maxDurability int) {
+	types.RegisterItemMigration(2, itemType, func(s interface{}) interface{} {
+		if state, ok := s.(BaseItemState); ok {
+			return DurableItemState{BaseItemState: state, Durability: maxDurability}
+		}
+		return s
+	})
+}
@@ -1,16 +1,16 @@
 package items_impl
 
 import (
-	"encoding/gob"
-
-	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func init() {
-	gob.Register(FlintItemState{})
-	types.NewFlintItem = NewFlintItem
+	types.RegisterItem(types.ItemDefinition{
+		Type:  types.FlintItem,
+		Key:   "bamboo:flint",
+		New:   NewFlintItem,
+		State: FlintItemState{},
+	})
 }
 
 type FlintItemState struct {
@@ -41,8 +41,8 @@ func (flint *FlintItem) Hash() uint64 {
 	return uint64(flint.id)
 }
 
-func (flint *FlintItem) Texture() *ebiten.Image {
-	return assets.Texture("flint").Texture()
+func (flint *FlintItem) TextureName() string {
+	return "flint"
 }
 
 func (item *FlintItem) ToolFamily() types.ToolFamily {
@@ -65,7 +65,7 @@ func (flint *FlintItem) UseTool(pos types.Vec2u) {
 		}
 	case types.SandBlock:
 		// Flint can be placed back on sand
-		types.GetCurrentWorld().SetBlock(pos.X, pos.Y, types.NewSandWithStonesBlock())
+		types.GetCurrentWorld().SetBlock(pos.X, pos.Y, types.NewBlock(types.SandWithStonesBlock))
 		types.GetPlayerInventory().RemoveItemByType(flint.Type(), 1)
 	}
 }
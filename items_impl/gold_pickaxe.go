@@ -0,0 +1,47 @@
+package items_impl
+
+import (
+	"github.com/3elDU/bamboo/types"
+)
+
+func init() {
+	types.RegisterItem(types.ItemDefinition{
+		Type:  types.GoldPickaxeItem,
+		Key:   "bamboo:gold_pickaxe",
+		New:   NewGoldPickaxeItem,
+		State: DurableItemState{},
+	})
+	registerDurabilityMigration(types.GoldPickaxeItem, goldPickaxeDurability)
+}
+
+const goldPickaxeDurability = 48
+
+type GoldPickaxeItem struct {
+	durableItem
+}
+
+func NewGoldPickaxeItem() types.Item {
+	return &GoldPickaxeItem{
+		durableItem: newDurableItem(types.GoldPickaxeItem, goldPickaxeDurability),
+	}
+}
+
+func (pickaxe *GoldPickaxeItem) Name() string {
+	return "Gold pickaxe"
+}
+func (pickaxe *GoldPickaxeItem) Description() string {
+	return ""
+}
+func (pickaxe *GoldPickaxeItem) TextureName() string {
+	return "gold_pickaxe"
+}
+
+func (pickaxe *GoldPickaxeItem) ToolFamily() types.ToolFamily {
+	return types.ToolFamilyPickaxe
+}
+func (pickaxe *GoldPickaxeItem) ToolStrength() types.ToolStrength {
+	return types.ToolStrengthGold
+}
+func (pickaxe *GoldPickaxeItem) UseTool(_ types.Vec2u) {
+
+}
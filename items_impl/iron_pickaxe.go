@@ -0,0 +1,47 @@
+package items_impl
+
+import (
+	"github.com/3elDU/bamboo/types"
+)
+
+func init() {
+	types.RegisterItem(types.ItemDefinition{
+		Type:  types.IronPickaxeItem,
+		Key:   "bamboo:iron_pickaxe",
+		New:   NewIronPickaxeItem,
+		State: DurableItemState{},
+	})
+	registerDurabilityMigration(types.IronPickaxeItem, ironPickaxeDurability)
+}
+
+const ironPickaxeDurability = 192
+
+type IronPickaxeItem struct {
+	durableItem
+}
+
+func NewIronPickaxeItem() types.Item {
+	return &IronPickaxeItem{
+		durableItem: newDurableItem(types.IronPickaxeItem, ironPickaxeDurability),
+	}
+}
+
+func (pickaxe *IronPickaxeItem) Name() string {
+	return "Iron pickaxe"
+}
+func (pickaxe *IronPickaxeItem) Description() string {
+	return ""
+}
+func (pickaxe *IronPickaxeItem) TextureName() string {
+	return "iron_pickaxe"
+}
+
+func (pickaxe *IronPickaxeItem) ToolFamily() types.ToolFamily {
+	return types.ToolFamilyPickaxe
+}
+func (pickaxe *IronPickaxeItem) ToolStrength() types.ToolStrength {
+	return types.ToolStrengthIron
+}
+func (pickaxe *IronPickaxeItem) UseTool(_ types.Vec2u) {
+
+}
@@ -1,17 +1,18 @@
 package items_impl
 
 import (
-	"encoding/gob"
-
 	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
-	"github.com/hajimehoshi/ebiten/v2"
 	"golang.org/x/exp/slices"
 )
 
 func init() {
-	gob.Register(PineSaplingItemState{})
-	types.NewPineSaplingItem = NewPineSaplingItem
+	types.RegisterItem(types.ItemDefinition{
+		Type:  types.PineSaplingItem,
+		Key:   "bamboo:pine_sapling",
+		New:   NewPineSaplingItem,
+		State: PineSaplingItemState{},
+	})
 }
 
 type PineSaplingItemState struct {
@@ -45,8 +46,8 @@ func (item *PineSaplingItem) BurningEnergy() float64 {
 	return 0.5
 }
 
-func (item *PineSaplingItem) Texture() *ebiten.Image {
-	return item.Tex.Texture()
+func (item *PineSaplingItem) TextureName() string {
+	return item.Tex.Name()
 }
 
 func (item *PineSaplingItem) Hash() uint64 {
@@ -70,7 +71,7 @@ func (item *PineSaplingItem) UseTool(pos types.Vec2u) {
 		return
 	}
 
-	types.GetCurrentWorld().SetBlock(pos.X, pos.Y, types.NewPineSaplingBlock())
+	types.GetCurrentWorld().SetBlock(pos.X, pos.Y, types.NewBlock(types.PineSaplingBlock))
 	types.GetPlayerInventory().RemoveItem(types.ItemSlot{
 		Item:     item,
 		Quantity: 1,
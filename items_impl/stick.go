@@ -1,16 +1,17 @@
 package items_impl
 
 import (
-	"encoding/gob"
-
 	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func init() {
-	types.NewStickItem = NewStickItem
-	gob.Register(StickItemState{})
+	types.RegisterItem(types.ItemDefinition{
+		Type:  types.StickItem,
+		Key:   "bamboo:stick",
+		New:   NewStickItem,
+		State: StickItemState{},
+	})
 }
 
 type StickItemState struct {
@@ -44,8 +45,8 @@ func (item *StickItem) BurningEnergy() float64 {
 	return 1
 }
 
-func (item *StickItem) Texture() *ebiten.Image {
-	return item.Tex.Texture()
+func (item *StickItem) TextureName() string {
+	return item.Tex.Name()
 }
 
 func (item *StickItem) Hash() uint64 {
@@ -78,7 +79,7 @@ func (item *StickItem) UseTool(pos types.Vec2u) {
 		return
 	}
 
-	types.GetCurrentWorld().SetBlock(pos.X, pos.Y, types.NewCampfireBlock())
+	types.GetCurrentWorld().SetBlock(pos.X, pos.Y, types.NewBlock(types.CampfireBlock))
 	types.GetPlayerInventory().RemoveItem(types.ItemSlot{Item: item, Quantity: 1})
 }
 
@@ -0,0 +1,56 @@
+package items_impl
+
+import (
+	"github.com/3elDU/bamboo/types"
+)
+
+func init() {
+	types.RegisterItem(types.ItemDefinition{
+		Type: types.UnknownItem,
+		Key:  "bamboo:unknown",
+		New:  func() types.Item { return NewUnknownItem("", nil) },
+	})
+	types.NewUnknownItem = NewUnknownItem
+}
+
+// Stands in for an item, which key isn't registered,
+// so the item isn't lost when the save is loaded by a version of the game that doesn't have it
+type UnknownItem struct {
+	baseItem
+	key   string
+	state interface{}
+}
+
+func NewUnknownItem(key string, state interface{}) types.Item {
+	return &UnknownItem{
+		baseItem: baseItem{id: types.UnknownItem},
+		key:      key,
+		state:    state,
+	}
+}
+
+func (item *UnknownItem) OriginalKey() string {
+	return item.key
+}
+
+// Placeholders for different items must never end up in one stack
+func (item *UnknownItem) Stackable() bool {
+	return false
+}
+
+func (item *UnknownItem) Name() string {
+	return "Unknown item"
+}
+func (item *UnknownItem) Description() string {
+	return item.key
+}
+func (item *UnknownItem) TextureName() string {
+	return "test_item"
+}
+
+func (item *UnknownItem) State() interface{} {
+	return item.state
+}
+func (item *UnknownItem) LoadState(s interface{}) {
+	item.state = s
+}
@@ -1,18 +1,19 @@
 package items_impl
 
 import (
-	"encoding/gob"
 	"fmt"
 	"log"
 
-	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/types"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func init() {
-	gob.Register(WateringCanState{})
-	types.NewWateringCanItem = NewWateringCanItem
+	types.RegisterItem(types.ItemDefinition{
+		Type:  types.WateringCanItem,
+		Key:   "bamboo:watering_can",
+		New:   NewWateringCanItem,
+		State: WateringCanState{},
+	})
 }
 
 type WateringCanState struct {
@@ -44,11 +45,11 @@ func (item *WateringCanItem) Description() string {
 	return fmt.Sprintf("Water left: %v", item.waterAmount)
 }
 
-func (item *WateringCanItem) Texture() *ebiten.Image {
+func (item *WateringCanItem) TextureName() string {
 	if item.waterAmount > 0 {
-		return assets.Texture("watering_can_with_water").Texture()
+		return "watering_can_with_water"
 	} else {
-		return assets.Texture("watering_can").Texture()
+		return "watering_can"
 	}
 }
 
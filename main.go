@@ -10,6 +10,7 @@ import (
 
 	"github.com/3elDU/bamboo/scene_manager"
 	"github.com/3elDU/bamboo/scenes"
+	"github.com/3elDU/bamboo/types"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/pkg/profile"
 	"golang.org/x/exp/slices"
@@ -39,6 +40,9 @@ func init() {
 }
 
 func main() {
+	// fail early, instead of when the missing block or item is loaded
+	types.CheckRegistries()
+
 	if slices.Contains(os.Environ(), "CPUPROFILE=1") {
 		log.Println("Starting with CPU profiling enabled")
 		defer profile.Start(profile.CPUProfile, profile.ProfilePath(".")).Stop()
@@ -5,6 +5,7 @@ import (
 	"log"
 	"reflect"
 
+	"github.com/3elDU/bamboo/clock"
 	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/event"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -32,10 +33,6 @@ type sceneManager struct {
 	overlay      Scene
 	stack        []Scene
 
-	// tick counter
-	// can be retrieved through Ticks() function
-	tickCounter uint64
-
 	// special flag, that is set in SceneManager.Exit()
 	terminated bool
 }
@@ -48,12 +45,6 @@ func init() {
 	}
 }
 
-// Ticks Returns internal tick counter that is incremented on each Update() call
-// Can be used for different timing purposes
-func Ticks() uint64 {
-	return manager.tickCounter
-}
-
 // Pop must be called from Scene.Update()
 // Exits current scene, and switches to next in the stack
 // If the stack is empty, exits
@@ -171,7 +162,7 @@ func (manager *sceneManager) Update() error {
 		manager.overlay.Update()
 	}
 
-	manager.tickCounter++
+	clock.Tick()
 
 	return nil
 }
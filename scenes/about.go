@@ -3,8 +3,8 @@ package scenes
 import (
 	"log"
 
-	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/scene_manager"
+	"github.com/3elDU/bamboo/textures"
 	"github.com/3elDU/bamboo/ui"
 	"github.com/MakeNowJust/heredoc"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -20,7 +20,7 @@ func NewAboutScene() *AboutScene {
 
 	return &AboutScene{
 		goBackEvent: goBackEvent,
-		view: ui.Screen(ui.TileBackgroundImage(assets.Texture("snow"),
+		view: ui.Screen(ui.TileBackgroundImage(textures.Texture("snow"),
 			ui.Center(ui.VStack().WithSpacing(1).WithChildren(
 				ui.Label(heredoc.Doc(`
 					Very important text...
@@ -0,0 +1,102 @@
+package scenes
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/3elDU/bamboo/colors"
+	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/game/archive"
+	"github.com/3elDU/bamboo/scene_manager"
+	"github.com/3elDU/bamboo/textures"
+	"github.com/3elDU/bamboo/ui"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Lists exported worlds in config.ExportDirectory, and imports selected ones
+type ImportScene struct {
+	view ui.Component
+
+	// path to the selected archive is transmitted through this channel
+	importArchive chan string
+	goBack        chan bool
+
+	// error from the last import
+	status string
+}
+
+func NewImportScene() *ImportScene {
+	scene := &ImportScene{
+		importArchive: make(chan string, 1),
+		goBack:        make(chan bool, 1),
+	}
+	scene.UpdateUI()
+	return scene
+}
+
+func (scene *ImportScene) UpdateUI() {
+	rootView := ui.VStack().WithSpacing(3).AlignChildren(ui.AlignCenter)
+	rootView.AddChild(ui.Label(fmt.Sprintf("Worlds in %v", config.ExportDirectory)))
+	if scene.status != "" {
+		rootView.AddChild(ui.ColoredLabel(scene.status, colors.C("red")))
+	}
+
+	archiveList := ui.VStack().WithSpacing(1).AlignChildren(ui.AlignCenter)
+	archives := archive.List()
+	if len(archives) == 0 {
+		archiveList.AddChild(ui.Label(fmt.Sprintf("Put %v files there to import them", archive.Extension)))
+	}
+	for _, path := range archives {
+		manifest, err := archive.ReadManifest(path)
+		if err != nil {
+			archiveList.AddChild(ui.ColoredLabel(fmt.Sprintf("%v: %v", filepath.Base(path), err), colors.C("red")))
+			continue
+		}
+
+		archiveList.AddChild(ui.HStack().WithSpacing(1).WithChildren(
+			ui.Label(fmt.Sprintf("%v (%v)", manifest.Name, filepath.Base(path))),
+			ui.Button(scene.importArchive, path, ui.Label("Import")),
+		))
+	}
+	rootView.AddChild(archiveList)
+
+	rootView.AddChild(ui.Button(scene.goBack, true, ui.Label("Go back")))
+
+	scene.view = ui.Screen(
+		ui.TileBackgroundImage(textures.Texture("snow"),
+			ui.Center(rootView),
+		),
+	)
+}
+
+func (scene *ImportScene) Destroy() {
+	log.Println("ImportScene.Destroy() called")
+}
+
+func (scene *ImportScene) Update() {
+	if err := scene.view.Update(); err != nil {
+		log.Panicf("failed to update a view: %v", err)
+	}
+
+	select {
+	case path := <-scene.importArchive:
+		if _, err := archive.Import(path); err != nil {
+			log.Printf("ImportScene - failed to import %v: %v", path, err)
+			scene.status = fmt.Sprintf("Failed to import: %v", err)
+			scene.UpdateUI()
+		} else {
+			// the world list picks the imported world up on the next rescan
+			scene_manager.Pop()
+		}
+	case <-scene.goBack:
+		scene_manager.Pop()
+	default:
+	}
+}
+
+func (scene *ImportScene) Draw(screen *ebiten.Image) {
+	if err := scene.view.Draw(screen, 0, 0); err != nil {
+		log.Panicf("ImportScene.view.Draw() - %v", err)
+	}
+}
@@ -3,9 +3,9 @@ package scenes
 import (
 	"log"
 
-	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/scene_manager"
+	"github.com/3elDU/bamboo/textures"
 	"github.com/3elDU/bamboo/ui"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -23,7 +23,7 @@ func NewMainMenuScene() *MainMenu {
 
 	return &MainMenu{
 		buttonPressed: buttonPressed,
-		view: ui.Screen(ui.TileBackgroundImage(assets.Texture("snow"), ui.Padding(0.5,
+		view: ui.Screen(ui.TileBackgroundImage(textures.Texture("snow"), ui.Padding(0.5,
 			ui.Overlay(
 				ui.VStack().WithProportions(0.4).WithChildren(
 					ui.Center(
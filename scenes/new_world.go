@@ -1,54 +1,207 @@
 package scenes
 
 import (
+	"context"
+	"fmt"
 	"hash/fnv"
+	"image"
+	"image/color"
 	"log"
 	"math/rand"
+	"strconv"
+	"sync"
 
 	"github.com/3elDU/bamboo/types"
-	"github.com/3elDU/bamboo/world"
 	"github.com/3elDU/bamboo/world_type"
+	"github.com/3elDU/bamboo/worldgen"
 
-	"github.com/3elDU/bamboo/assets"
+	"github.com/3elDU/bamboo/clock"
+	"github.com/3elDU/bamboo/colors"
+	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/game"
+	"github.com/3elDU/bamboo/game/player"
 	"github.com/3elDU/bamboo/scene_manager"
+	"github.com/3elDU/bamboo/textures"
 	"github.com/3elDU/bamboo/ui"
+	"github.com/3elDU/bamboo/world"
 	"github.com/google/uuid"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// Preview map of the world has at most this many pixels on each side
+const previewResolution = 128
+
+// Preview is refreshed, after the settings haven't changed for this many ticks,
+// so it isn't regenerated on every typed character
+const previewDelay = 20
+
 type NewWorldScene struct {
 	view ui.Component
 
 	// form results will be received through this channel
 	// first string is world name, second is world seed
 	formData chan []string
+	form     *ui.FormComponent
+	// used, when the seed is left empty
+	randomSeed int64
+	randomize  chan bool
+
+	// worldgen presets, the selected one is cycled through with a button
+	presets        []types.WorldgenPreset
+	selectedPreset int
+	presetLabel    *ui.LabelComponent
+	nextPreset     chan bool
+
+	// same for size and shape of the world
+	selectedSize  int
+	sizeLabel     *ui.LabelComponent
+	nextSize      chan bool
+	selectedShape int
+	shapeLabel    *ui.LabelComponent
+	nextShape     chan bool
+	// codec doesn't affect the generated world, so it isn't a part of newWorldSettings
+	codecs        []string
+	selectedCodec int
+	codecLabel    *ui.LabelComponent
+	nextCodec     chan bool
+
+	// settings, that the preview was last requested for, nil before the first request
+	previewRequested *newWorldSettings
+	// when settings change, the preview is requested after previewDelay
+	changedSettings newWorldSettings
+	changedAt       uint64
+	// cancels the preview goroutine, that is still running for older settings
+	cancelPreview context.CancelFunc
+	// written by the preview goroutine
+	previewMutex  sync.Mutex
+	latestPreview *seedPreview
+
+	previewImage *ebiten.Image
+	previewLabel *ui.LabelComponent
 
 	goBack chan bool
 }
 
+// Everything that affects the generated world
+type newWorldSettings struct {
+	seed                int64
+	preset, size, shape int
+}
+
+type seedPreview struct {
+	settings newWorldSettings
+	preview  *worldgen.Preview
+	spawn    types.Vec2u
+	// false, if the spawn point search gave up
+	spawnFound bool
+}
+
+type worldSize struct {
+	name string
+	size uint64
+}
+
+var worldSizes = []worldSize{
+	{"medium", config.OverworldSize},
+	{"large", config.LargeOverworldSize},
+	{"small", config.SmallOverworldSize},
+}
+
+var worldShapes = []types.WorldShape{types.SingleIslandShape, types.ArchipelagoShape, types.ContinentShape}
+
 func NewNewWorldScene() *NewWorldScene {
 	formData := make(chan []string, 1)
+	nextPreset := make(chan bool, 1)
+	nextSize := make(chan bool, 1)
+	nextShape := make(chan bool, 1)
+	nextCodec := make(chan bool, 1)
 	goBack := make(chan bool, 1)
 
+	randomize := make(chan bool, 1)
+
+	presets := worldgen.LoadPresets()
+	presetLabel := ui.Label(presetTitle(presets[0]))
+	sizeLabel := ui.Label(sizeTitle(worldSizes[0]))
+	shapeLabel := ui.Label(shapeTitle(worldShapes[0]))
+
+	// the default codec is selected first
+	codecs := world.CodecNames()
+	selectedCodec := 0
+	for i, name := range codecs {
+		if name == config.ChunkCodec {
+			selectedCodec = i
+		}
+	}
+	codecLabel := ui.Label(codecTitle(codecs[selectedCodec]))
+
+	form := ui.Form(
+		"Create a new world",
+		formData,
+		ui.FormPrompt{Title: "World name"},
+		ui.FormPrompt{Title: "World seed (optional)"},
+	)
+	previewImage := ebiten.NewImage(previewResolution, previewResolution)
+	previewImage.Fill(colors.C("darkgray"))
+	previewLabel := ui.Label("Generating preview...")
+
 	return &NewWorldScene{
-		formData: formData,
-		goBack:   goBack,
-
-		view: ui.Screen(ui.BackgroundImage(ui.BackgroundTile, assets.Texture("snow").Texture(), ui.Center(
-			ui.VStack().WithSpacing(1.0).AlignChildren(ui.AlignCenter).WithChildren(
-				ui.Form(
-					"Create a new world",
-					formData,
-					ui.FormPrompt{Title: "World name"},
-					ui.FormPrompt{Title: "World seed (optional)"},
+		formData:      formData,
+		form:          form,
+		randomSeed:    rand.Int63(),
+		randomize:     randomize,
+		presets:       presets,
+		presetLabel:   presetLabel,
+		nextPreset:    nextPreset,
+		sizeLabel:     sizeLabel,
+		nextSize:      nextSize,
+		shapeLabel:    shapeLabel,
+		nextShape:     nextShape,
+		codecs:        codecs,
+		selectedCodec: selectedCodec,
+		codecLabel:    codecLabel,
+		nextCodec:     nextCodec,
+		previewImage:  previewImage,
+		previewLabel:  previewLabel,
+		goBack:        goBack,
+
+		view: ui.Screen(ui.BackgroundImage(ui.BackgroundTile, textures.Texture("snow"), ui.Center(
+			ui.HStack().WithSpacing(3).WithChildren(
+				ui.VStack().WithSpacing(1.0).AlignChildren(ui.AlignCenter).WithChildren(
+					form,
+					ui.HStack().WithSpacing(1).WithChildren(
+						ui.Button(nextPreset, true, presetLabel),
+						ui.Button(nextSize, true, sizeLabel),
+						ui.Button(nextShape, true, shapeLabel),
+					),
+					ui.Button(nextCodec, true, codecLabel),
+					ui.Button(goBack, true, ui.Label("Go back")),
+				),
+				ui.VStack().WithSpacing(1.0).AlignChildren(ui.AlignCenter).WithChildren(
+					ui.Image(previewImage),
+					previewLabel,
+					ui.Button(randomize, true, ui.Label("Randomize seed")),
 				),
-				ui.Button(goBack, true, ui.Label("Go back")),
 			),
 		))),
 	}
 }
 
+func presetTitle(preset types.WorldgenPreset) string {
+	return "Preset: " + preset.Name
+}
+
+func sizeTitle(size worldSize) string {
+	return fmt.Sprintf("Size: %v (%v)", size.name, size.size)
+}
+
+func shapeTitle(shape types.WorldShape) string {
+	return "Shape: " + shape.String()
+}
+
+func codecTitle(codec string) string {
+	return "Compression: " + codec
+}
+
 func seedFromString(s string) (seed int64) {
 	if s == "" {
 		// if seed string is empty, generate a random one instead
@@ -62,32 +215,169 @@ func seedFromString(s string) (seed int64) {
 	return
 }
 
+func (s *NewWorldScene) settings() newWorldSettings {
+	seed := s.randomSeed
+	if seedString := s.form.Input(1); seedString != "" {
+		seed = seedFromString(seedString)
+	}
+	return newWorldSettings{
+		seed:   seed,
+		preset: s.selectedPreset,
+		size:   s.selectedSize,
+		shape:  s.selectedShape,
+	}
+}
+
+// Metadata of the world, that will be created with those settings
+func (s *NewWorldScene) metadata(settings newWorldSettings) types.Save {
+	preset := s.presets[settings.preset]
+	size := worldSizes[settings.size].size
+	return types.Save{
+		Seed:      settings.seed,
+		WorldType: world_type.Overworld,
+		Size:      types.Vec2u{X: size, Y: size},
+		Shape:     worldShapes[settings.shape],
+		Preset:    &preset,
+	}
+}
+
+// Requests a new preview, after the settings stop changing, and shows the finished one
+func (s *NewWorldScene) updatePreview() {
+	settings := s.settings()
+	if settings != s.changedSettings {
+		s.changedSettings = settings
+		s.changedAt = clock.Ticks()
+	}
+
+	requested := s.previewRequested != nil && *s.previewRequested == settings
+	if !requested && clock.Ticks()-s.changedAt >= previewDelay {
+		s.previewRequested = &settings
+		s.previewLabel.SetText("Generating preview...")
+
+		if s.cancelPreview != nil {
+			s.cancelPreview()
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		s.cancelPreview = cancel
+
+		metadata := s.metadata(settings)
+		go func() {
+			preview, err := worldgen.NewPreview(ctx, metadata, previewResolution)
+			if err != nil {
+				// settings have changed, another preview is being generated already
+				return
+			}
+			// only block types are generated here, the blocks themselves are never put into a world
+			spawn, found := player.SearchSpawnPoint(ctx, metadata, preview.BlockTypeAt)
+			if ctx.Err() != nil {
+				return
+			}
+
+			s.previewMutex.Lock()
+			s.latestPreview = &seedPreview{settings: settings, preview: preview, spawn: spawn, spawnFound: found}
+			s.previewMutex.Unlock()
+		}()
+	}
+
+	s.previewMutex.Lock()
+	latest := s.latestPreview
+	s.latestPreview = nil
+	s.previewMutex.Unlock()
+
+	// previews for outdated settings are thrown away
+	if latest != nil && s.previewRequested != nil && latest.settings == *s.previewRequested {
+		s.previewImage.WritePixels(renderPreview(latest).Pix)
+		if latest.spawnFound {
+			s.previewLabel.SetText(fmt.Sprintf("Spawn point: %v, %v", latest.spawn.X, latest.spawn.Y))
+		} else {
+			s.previewLabel.SetText("No land to spawn on")
+		}
+	}
+}
+
+var previewColors = map[types.BlockType]string{
+	types.WaterBlock: "darkblue",
+	types.SandBlock:  "yellow",
+	types.GrassBlock: "green",
+	types.SnowBlock:  "white",
+}
+
+func renderPreview(latest *seedPreview) *image.RGBA {
+	preview := latest.preview
+	img := image.NewRGBA(image.Rect(0, 0, previewResolution, previewResolution))
+	for x := 0; x < previewResolution; x++ {
+		for y := 0; y < previewResolution; y++ {
+			clr := colors.C("darkgray")
+			if x < preview.Width && y < preview.Height {
+				if name, exists := previewColors[preview.Blocks[x][y]]; exists {
+					clr = colors.C(name)
+				}
+			}
+			img.Set(x, y, clr)
+		}
+	}
+
+	if !latest.spawnFound {
+		return img
+	}
+
+	// mark the spawn point with a cross
+	sx, sy := int(latest.spawn.X/preview.Step), int(latest.spawn.Y/preview.Step)
+	for i := -2; i <= 2; i++ {
+		img.Set(sx+i, sy, colors.C("red"))
+		img.Set(sx, sy+i, colors.C("red"))
+	}
+	img.Set(sx, sy, color.Black)
+	return img
+}
+
 func (s *NewWorldScene) Update() {
 	if err := s.view.Update(); err != nil {
 		log.Panicf("failed to update a viev: %v", err)
 	}
+	s.updatePreview()
 
 	select {
+	case <-s.randomize:
+		s.form.SetInput(1, strconv.FormatInt(rand.Int63(), 10))
 	case <-s.goBack:
 		scene_manager.Pop()
+	case <-s.nextPreset:
+		s.selectedPreset = (s.selectedPreset + 1) % len(s.presets)
+		s.presetLabel.SetText(presetTitle(s.presets[s.selectedPreset]))
+	case <-s.nextSize:
+		s.selectedSize = (s.selectedSize + 1) % len(worldSizes)
+		s.sizeLabel.SetText(sizeTitle(worldSizes[s.selectedSize]))
+	case <-s.nextShape:
+		s.selectedShape = (s.selectedShape + 1) % len(worldShapes)
+		s.shapeLabel.SetText(shapeTitle(worldShapes[s.selectedShape]))
+	case <-s.nextCodec:
+		s.selectedCodec = (s.selectedCodec + 1) % len(s.codecs)
+		s.codecLabel.SetText(codecTitle(s.codecs[s.selectedCodec]))
 	case formData := <-s.formData:
 		worldName, seedString := formData[0], formData[1]
-		seed := seedFromString(seedString)
-
-		scene_manager.ReplaceAndSwitch(game.NewGameScene(types.Save{
-			Name:      worldName,
-			BaseUUID:  uuid.New(),
-			UUID:      uuid.New(),
-			Seed:      seed,
-			WorldType: world_type.Overworld,
-			Size:      world.SizeForWorldType(world_type.Overworld),
-		}))
+		settings := s.settings()
+		// the form is sent from a goroutine, the input may have changed since then
+		settings.seed = s.randomSeed
+		if seedString != "" {
+			settings.seed = seedFromString(seedString)
+		}
+
+		metadata := s.metadata(settings)
+		metadata.Name = worldName
+		metadata.BaseUUID = uuid.New()
+		metadata.UUID = uuid.New()
+		metadata.Codec = s.codecs[s.selectedCodec]
+		scene_manager.ReplaceAndSwitch(game.NewGameScene(metadata))
 	default:
 	}
 }
 
-func (*NewWorldScene) Destroy() {
+func (s *NewWorldScene) Destroy() {
 	log.Println("NewWorldScene.Destroy() called")
+	if s.cancelPreview != nil {
+		s.cancelPreview()
+	}
 }
 
 func (s *NewWorldScene) Draw(screen *ebiten.Image) {
@@ -0,0 +1,129 @@
+package scenes
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/scene_manager"
+	"github.com/3elDU/bamboo/textures"
+	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/ui"
+	"github.com/3elDU/bamboo/world"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Lists snapshots of a single world, and lets the user restore or delete them
+type SnapshotListScene struct {
+	metadata  types.Save
+	snapshots []world.Snapshot
+	view      ui.Component
+
+	restoreSnapshot chan world.Snapshot
+	deleteSnapshot  chan world.Snapshot
+	createSnapshot  chan bool
+	prune           chan bool
+	goBack          chan bool
+}
+
+func NewSnapshotListScene(metadata types.Save) *SnapshotListScene {
+	scene := &SnapshotListScene{
+		metadata: metadata,
+
+		restoreSnapshot: make(chan world.Snapshot, 1),
+		deleteSnapshot:  make(chan world.Snapshot, 1),
+		createSnapshot:  make(chan bool, 1),
+		prune:           make(chan bool, 1),
+		goBack:          make(chan bool, 1),
+	}
+	scene.Scan()
+	return scene
+}
+
+// Scan rereads the list of snapshots, and rebuilds the UI
+func (scene *SnapshotListScene) Scan() {
+	scene.snapshots = world.ListSnapshots(scene.metadata.BaseUUID)
+	scene.UpdateUI()
+}
+
+func (scene *SnapshotListScene) UpdateUI() {
+	rootView := ui.VStack().WithSpacing(3).AlignChildren(ui.AlignCenter)
+	rootView.AddChild(ui.Label(fmt.Sprintf("Snapshots of \"%v\"", scene.metadata.Name)))
+
+	snapshotList := ui.VStack().WithSpacing(1).AlignChildren(ui.AlignCenter)
+	if len(scene.snapshots) == 0 {
+		snapshotList.AddChild(ui.Label("No snapshots yet"))
+	}
+	for _, snapshot := range scene.snapshots {
+		snapshotList.AddChild(ui.HStack().WithSpacing(1).WithChildren(
+			ui.Label(fmt.Sprintf("%v (%v KiB)", snapshot.Time.Format("2006-01-02 15:04:05"), snapshot.Size/1024)),
+			ui.Button(scene.restoreSnapshot, snapshot, ui.Label("Restore")),
+			ui.Button(scene.deleteSnapshot, snapshot, ui.Label("Delete")),
+		))
+	}
+	rootView.AddChild(snapshotList)
+
+	rootView.AddChild(ui.HStack().WithSpacing(1).WithChildren(
+		ui.Button(scene.createSnapshot, true, ui.Label("Create snapshot")),
+		ui.Button(scene.prune, true, ui.Label(fmt.Sprintf("Keep %v newest", config.SnapshotRetention))),
+		ui.Button(scene.goBack, true, ui.Label("Go back")),
+	))
+
+	scene.view = ui.Screen(
+		ui.TileBackgroundImage(textures.Texture("snow"),
+			ui.Center(rootView),
+		),
+	)
+}
+
+func (scene *SnapshotListScene) Destroy() {
+	log.Println("SnapshotListScene.Destroy() called")
+}
+
+func (scene *SnapshotListScene) Update() {
+	if err := scene.view.Update(); err != nil {
+		log.Panicf("failed to update a view: %v", err)
+	}
+
+	select {
+	case snapshot := <-scene.restoreSnapshot:
+		scene_manager.PushAndSwitch(NewConfirmationScene(
+			fmt.Sprintf("Replace the world with the snapshot from %v?", snapshot.Time.Format("2006-01-02 15:04:05")),
+			func() {
+				if err := world.RestoreSnapshot(snapshot); err != nil {
+					log.Printf("SnapshotListScene - failed to restore snapshot: %v", err)
+				}
+				scene.Scan()
+			},
+		))
+	case snapshot := <-scene.deleteSnapshot:
+		scene_manager.PushAndSwitch(NewConfirmationScene(
+			fmt.Sprintf("Delete the snapshot from %v?", snapshot.Time.Format("2006-01-02 15:04:05")),
+			func() {
+				if err := world.DeleteSnapshot(snapshot); err != nil {
+					log.Printf("SnapshotListScene - failed to delete snapshot: %v", err)
+				}
+				scene.Scan()
+			},
+		))
+	case <-scene.createSnapshot:
+		if _, err := world.CreateSnapshot(scene.metadata.BaseUUID, nil); err != nil {
+			log.Printf("SnapshotListScene - failed to create snapshot: %v", err)
+		}
+		scene.Scan()
+	case <-scene.prune:
+		if err := world.PruneSnapshots(scene.metadata.BaseUUID, config.SnapshotRetention); err != nil {
+			log.Printf("SnapshotListScene - failed to prune snapshots: %v", err)
+		}
+		scene.Scan()
+	case <-scene.goBack:
+		scene_manager.Pop()
+	default:
+	}
+}
+
+func (scene *SnapshotListScene) Draw(screen *ebiten.Image) {
+	if err := scene.view.Draw(screen, 0, 0); err != nil {
+		log.Panicf("SnapshotListScene.view.Draw() - %v", err)
+	}
+}
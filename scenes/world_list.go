@@ -12,34 +12,62 @@ import (
 	"github.com/3elDU/bamboo/types"
 	"github.com/3elDU/bamboo/world_type"
 
-	"github.com/3elDU/bamboo/assets"
+	"github.com/3elDU/bamboo/clock"
+	"github.com/3elDU/bamboo/colors"
 	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/game"
+	"github.com/3elDU/bamboo/game/archive"
+	"github.com/3elDU/bamboo/game/player"
 	"github.com/3elDU/bamboo/scene_manager"
+	"github.com/3elDU/bamboo/textures"
 	"github.com/3elDU/bamboo/ui"
 	"github.com/3elDU/bamboo/world"
+	"github.com/google/uuid"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type WorldListScene struct {
 	worldList []types.Save
-	view      ui.Component
+	// worlds, which had corrupted data repaired on load
+	repaired map[uuid.UUID]bool
+	// worlds, which metadata is corrupted.
+	// Their metadata in the list is the copy from the player stack, if there is one.
+	damaged map[uuid.UUID]bool
+	view    ui.Component
 
 	// when the world will be selected by the user,
 	// world name will be transmitted through this channel
 	selectedWorld chan types.Save
 	deleteWorld   chan types.Save
+	snapshots     chan types.Save
+	exportWorld   chan types.Save
 
 	// when the "New world" button will be pressed
 	// the event will be transmitted through this channel
-	newWorld chan bool
+	newWorld    chan bool
+	importWorld chan bool
 	// same idead as for newWorld
 	goBack chan bool
+
+	// result of the last export, shown above the world list
+	status string
+
+	// set while the snapshot of the selected world is created, the world is loaded after that
+	loading *pendingLoad
+}
+
+type pendingLoad struct {
+	save     types.Save
+	progress *world.SnapshotProgress
+	// closed, when the snapshot is created
+	done chan struct{}
 }
 
 // Scan scans the save folder for worlds
 func (scene *WorldListScene) Scan() {
 	worldList := make([]types.Save, 0)
+	repaired := make(map[uuid.UUID]bool)
+	damaged := make(map[uuid.UUID]bool)
 	filepath.WalkDir(config.WorldSaveDirectory, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -62,7 +90,12 @@ func (scene *WorldListScene) Scan() {
 		decoder := gob.NewDecoder(bytes.NewReader(worldInfo))
 		worldMetadata := new(types.Save)
 		if err = decoder.Decode(worldMetadata); err != nil {
-			return err
+			// corrupted metadata, the world is listed as damaged after the scan,
+			// unless it turns out to be a cave
+			if baseUUID, err := uuid.Parse(filepath.Base(filepath.Dir(path))); err == nil {
+				damaged[baseUUID] = true
+			}
+			return nil
 		}
 
 		if worldMetadata.WorldType != world_type.Overworld {
@@ -70,25 +103,85 @@ func (scene *WorldListScene) Scan() {
 		}
 
 		worldList = append(worldList, *worldMetadata)
+		repaired[worldMetadata.BaseUUID] = world.WasRepaired(worldMetadata.BaseUUID)
 
 		return nil
 	})
+
+	for baseUUID := range damaged {
+		listed := false
+		for _, metadata := range worldList {
+			listed = listed || metadata.BaseUUID == baseUUID
+		}
+		if listed {
+			// the overworld is fine, so one of the caves is damaged, it is restored when the player enters it
+			delete(damaged, baseUUID)
+			continue
+		}
+
+		worldList = append(worldList, damagedWorldMetadata(baseUUID))
+		repaired[baseUUID] = world.WasRepaired(baseUUID)
+	}
+
 	scene.worldList = worldList
+	scene.repaired = repaired
+	scene.damaged = damaged
+}
+
+// The player stack keeps a copy of metadata of each world the player is in,
+// and the bottom of the stack is always the overworld
+func damagedWorldMetadata(baseUUID uuid.UUID) types.Save {
+	stack, err := player.ReadPlayerStack(baseUUID)
+	if err != nil || len(stack.Stack) == 0 {
+		// game.LoadGameScene() will report, that the world can't be restored
+		return types.Save{Name: "?", BaseUUID: baseUUID}
+	}
+	return stack.Stack[0].SelectedWorld
 }
 
 func (scene *WorldListScene) UpdateUI() {
+	if scene.loading != nil {
+		// no buttons while the snapshot is created, only the progress
+		scene.view = ui.Screen(
+			ui.TileBackgroundImage(textures.Texture("snow"),
+				ui.Center(ui.Label(fmt.Sprintf(
+					"Creating a snapshot of \"%v\": %v%%",
+					scene.loading.save.Name, scene.loading.progress.Percent(),
+				))),
+			),
+		)
+		return
+	}
+
 	rootView := ui.VStack().WithSpacing(3).AlignChildren(ui.AlignCenter)
 	worldList := ui.VStack().WithSpacing(2.0).AlignChildren(ui.AlignCenter)
+	if scene.status != "" {
+		rootView.AddChild(ui.Label(scene.status))
+	}
 
 	for _, currentWorld := range scene.worldList {
+		info := ui.HStack().WithSpacing(2).WithChildren(
+			ui.Label(fmt.Sprintf("Name: %v", currentWorld.Name)),
+			ui.Label(fmt.Sprintf("Seed: %v", currentWorld.Seed)),
+		)
+		if scene.repaired[currentWorld.BaseUUID] {
+			// warn the user, that some of the world data was corrupted and had to be regenerated
+			info.AddChild(ui.ColoredLabel("Repaired", colors.C("red")))
+		}
+		playLabel := "Play"
+		if scene.damaged[currentWorld.BaseUUID] {
+			// metadata is restored from the player stack, when the world is loaded
+			info.AddChild(ui.ColoredLabel("Damaged", colors.C("red")))
+			playLabel = "Recover"
+		}
+
 		// assemble a view for each world
 		worldList.AddChild(ui.VStack().AlignChildren(ui.AlignCenter).WithChildren(
-			ui.HStack().WithSpacing(2).WithChildren(
-				ui.Label(fmt.Sprintf("Name: %v", currentWorld.Name)),
-				ui.Label(fmt.Sprintf("Seed: %v", currentWorld.Seed)),
-			),
+			info,
 			ui.HStack().WithSpacing(1).WithChildren(
-				ui.Button(scene.selectedWorld, currentWorld, ui.Label("Play")),
+				ui.Button(scene.selectedWorld, currentWorld, ui.Label(playLabel)),
+				ui.Button(scene.snapshots, currentWorld, ui.Label("Snapshots")),
+				ui.Button(scene.exportWorld, currentWorld, ui.Label("Export")),
 				ui.Button(scene.deleteWorld, currentWorld, ui.Label("Delete")),
 			),
 		))
@@ -97,11 +190,12 @@ func (scene *WorldListScene) UpdateUI() {
 
 	rootView.AddChild(ui.HStack().WithSpacing(1).WithChildren(
 		ui.Button(scene.newWorld, true, ui.Label("New world")),
+		ui.Button(scene.importWorld, true, ui.Label("Import")),
 		ui.Button(scene.goBack, true, ui.Label("Go back")),
 	))
 
 	scene.view = ui.Screen(
-		ui.TileBackgroundImage(assets.Texture("snow"),
+		ui.TileBackgroundImage(textures.Texture("snow"),
 			ui.Center(rootView),
 		),
 	)
@@ -111,6 +205,9 @@ func NewWorldListScene() *WorldListScene {
 	scene := &WorldListScene{
 		selectedWorld: make(chan types.Save, 1),
 		deleteWorld:   make(chan types.Save, 1),
+		snapshots:     make(chan types.Save, 1),
+		exportWorld:   make(chan types.Save, 1),
+		importWorld:   make(chan bool, 1),
 		newWorld:      make(chan bool, 1),
 		goBack:        make(chan bool, 1),
 	}
@@ -123,9 +220,58 @@ func (scene *WorldListScene) Destroy() {
 	log.Println("worldListScene.Destroy() called")
 }
 
+// Snapshots are created in the background, so large worlds don't freeze the game
+func (scene *WorldListScene) startLoading(save types.Save) {
+	loading := &pendingLoad{
+		save:     save,
+		progress: new(world.SnapshotProgress),
+		done:     make(chan struct{}),
+	}
+	go func() {
+		defer close(loading.done)
+		if _, err := world.CreateSnapshot(save.BaseUUID, loading.progress); err != nil {
+			log.Printf("worldListScene - failed to create snapshot: %v", err)
+		} else if err := world.PruneSnapshots(save.BaseUUID, config.SnapshotRetention); err != nil {
+			log.Printf("worldListScene - failed to prune snapshots: %v", err)
+		}
+	}()
+
+	scene.loading = loading
+	scene.UpdateUI()
+}
+
+func (scene *WorldListScene) load(save types.Save) {
+	// the player has seen the "Repaired" badge by now
+	world.MarkRepairsSeen(save.BaseUUID)
+
+	gameScene, err := game.LoadGameScene(save)
+	if err != nil {
+		log.Printf("worldListScene - failed to load the world: %v", err)
+		scene.status = fmt.Sprintf("Failed to load \"%v\": %v", save.Name, err)
+		scene.UpdateUI()
+		return
+	}
+	scene_manager.ReplaceAndSwitch(gameScene)
+}
+
 func (scene *WorldListScene) Update() {
+	if scene.loading != nil {
+		select {
+		case <-scene.loading.done:
+			save := scene.loading.save
+			scene.loading = nil
+			scene.load(save)
+		default:
+			// refresh the progress a few times per second
+			if clock.Ticks()%10 == 0 {
+				scene.UpdateUI()
+			}
+		}
+		return
+	}
+
 	// Rescan the saves folder each 60 ticks ( 1 second )
-	if scene_manager.Ticks()%60 == 0 {
+	if clock.Ticks()%60 == 0 {
 		scene.Scan()
 		scene.UpdateUI()
 	}
@@ -137,12 +283,28 @@ func (scene *WorldListScene) Update() {
 	select {
 	case save := <-scene.selectedWorld:
 		log.Printf("worldListScene - Selected world '%v'", save)
-		scene_manager.ReplaceAndSwitch(game.LoadGameScene(save))
+		if config.SnapshotBeforeLoad {
+			scene.startLoading(save)
+			break
+		}
+		scene.load(save)
 	case <-scene.newWorld:
 		log.Println("worldListScene - New world")
 		scene_manager.PushAndSwitch(NewNewWorldScene())
 	case <-scene.goBack:
 		scene_manager.Pop()
+	case metadata := <-scene.exportWorld:
+		if path, err := archive.Export(metadata); err != nil {
+			log.Printf("worldListScene - failed to export world: %v", err)
+			scene.status = fmt.Sprintf("Failed to export \"%v\"", metadata.Name)
+		} else {
+			scene.status = fmt.Sprintf("Exported to %v", path)
+		}
+		scene.UpdateUI()
+	case <-scene.importWorld:
+		scene_manager.PushAndSwitch(NewImportScene())
+	case metadata := <-scene.snapshots:
+		scene_manager.PushAndSwitch(NewSnapshotListScene(metadata))
 	case metadata := <-scene.deleteWorld:
 		scene_manager.PushAndSwitch(NewConfirmationScene(
 			fmt.Sprintf("Are you sure to do delete the world \"%v\"?", metadata.Name),
@@ -157,7 +319,7 @@ func (scene *WorldListScene) Update() {
 }
 
 func (scene *WorldListScene) Draw(screen *ebiten.Image) {
-	if scene_manager.Ticks()%60 == 0 || scene.view == nil {
+	if clock.Ticks()%60 == 0 || scene.view == nil {
 		scene.UpdateUI()
 	}
 	if err := scene.view.Draw(screen, 0, 0); err != nil {
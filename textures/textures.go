@@ -0,0 +1,66 @@
+// Package textures uploads textures from the assets to the GPU, when they are first drawn.
+// Importing ebiten initializes the window system, so only the game itself should import this package.
+// Blocks, items and the world only refer to textures by name, see types.Texture.
+package textures
+
+import (
+	"log"
+
+	"github.com/3elDU/bamboo/assets"
+	"github.com/3elDU/bamboo/config"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type connectedKey struct {
+	baseName string
+	sides    [4]bool
+}
+
+var (
+	textures          = make(map[string]*ebiten.Image)
+	connectedTextures = make(map[connectedKey]*ebiten.Image)
+)
+
+// Texture panicks when a specified texture doesn't exist
+func Texture(name string) *ebiten.Image {
+	if tex, exists := textures[name]; exists {
+		return tex
+	}
+
+	img, exists := assets.Image(name)
+	if !exists {
+		log.Panicf("texture %v doesn't exist", name)
+	}
+	tex := ebiten.NewImageFromImage(img)
+	textures[name] = tex
+	return tex
+}
+
+// Connected returns the sub-texture of a connected texture atlas.
+// Sides go in order: left, right, top, bottom.
+// Panicks when a specified texture doesn't exist
+func Connected(baseName string, sides [4]bool) *ebiten.Image {
+	key := connectedKey{baseName: baseName, sides: sides}
+	if tex, exists := connectedTextures[key]; exists {
+		return tex
+	}
+
+	img, exists := assets.ConnectedImage(baseName, sides)
+	if !exists {
+		log.Panicf("connected texture %v %v doesn't exist", baseName, sides)
+	}
+	tex := ebiten.NewImageFromImage(img)
+	connectedTextures[key] = tex
+	return tex
+}
+
+// Returns size of the texture, multiplied by ui scaling
+// Useful for UI elements
+func ScaledSize(name string) (float64, float64) {
+	bounds := Texture(name).Bounds()
+	return float64(bounds.Dx()) * config.UIScaling, float64(bounds.Dy()) * config.UIScaling
+}
+
+func DefaultFont() *ebiten.Image {
+	return Texture("font")
+}
@@ -0,0 +1,122 @@
+package types
+
+import (
+	"encoding/gob"
+	"log"
+	"strings"
+)
+
+// Everything the game needs to know to create a block.
+// Each block implementation registers its definition from init().
+type BlockDefinition struct {
+	Type BlockType
+	// Stable name of the block, like "bamboo:stone".
+	// Saves refer to blocks by key, so it must never change, while the numeric ID can.
+	Key string
+	// Creates the block with default state
+	New func() Block
+	// Value of the type returned from Block.State(), it is registered with gob.
+	// Can be nil, if the block doesn't have its own state type.
+	State interface{}
+}
+
+var (
+	blocksByType = make(map[BlockType]BlockDefinition)
+	blocksByKey  = make(map[string]BlockDefinition)
+
+	// Blocks defined in data files, that Go code doesn't refer to, get IDs after the built-in ones
+	nextBlockType = builtinBlockTypes
+)
+
+// RegisterBlock adds the block to the registry. Panics on duplicate IDs and keys.
+// Must be called from init() of the block implementation.
+func RegisterBlock(definition BlockDefinition) {
+	if definition.New == nil {
+		log.Panicf("RegisterBlock() - %v has no constructor", definition.Type)
+	}
+	if !strings.Contains(definition.Key, ":") {
+		log.Panicf("RegisterBlock() - key %q of %v must be namespaced, like \"bamboo:stone\"", definition.Key, definition.Type)
+	}
+	if existing, exists := blocksByType[definition.Type]; exists {
+		log.Panicf("RegisterBlock() - %v is already registered with key %q", definition.Type, existing.Key)
+	}
+	if existing, exists := blocksByKey[definition.Key]; exists {
+		log.Panicf("RegisterBlock() - key %q is already taken by %v", definition.Key, existing.Type)
+	}
+
+	if definition.State != nil {
+		gob.Register(definition.State)
+	}
+
+	blocksByType[definition.Type] = definition
+	blocksByKey[definition.Key] = definition
+}
+
+// CheckBlockRegistry panics, if any of the built-in block types has no name, or is not registered.
+// Called on startup, after all block implementations are imported.
+func CheckBlockRegistry() {
+	for id, definition := range blocksByType {
+		if id < 0 || id >= nextBlockType {
+			log.Panicf("CheckBlockRegistry() - %q is registered with unknown block type %v", definition.Key, id)
+		}
+	}
+
+	missing := make([]string, 0)
+	for id := BlockType(0); id < builtinBlockTypes; id++ {
+		if blockTypeNames[id] == "" {
+			log.Panicf("CheckBlockRegistry() - block type %d has no name in blockTypeNames", int(id))
+		}
+		if _, exists := blocksByType[id]; !exists {
+			missing = append(missing, id.String())
+		}
+	}
+	if len(missing) > 0 {
+		log.Panicf("CheckBlockRegistry() - blocks are not registered: %v", strings.Join(missing, ", "))
+	}
+}
+
+// AllocateBlockType returns a new ID for a block, that isn't part of the BlockType enum
+func AllocateBlockType() BlockType {
+	id := nextBlockType
+	nextBlockType++
+	return id
+}
+
+// Returns the built-in block type by its name in the enum, like "StoneBlock"
+func BlockTypeByName(name string) (BlockType, bool) {
+	for id, typeName := range blockTypeNames {
+		if typeName == name {
+			return BlockType(id), true
+		}
+	}
+	return 0, false
+}
+
+// NewBlock creates the block with given ID, with default state
+func NewBlock(id BlockType) Block {
+	definition, exists := blocksByType[id]
+	if !exists {
+		log.Panicf("NewBlock() - block %v is not registered", id)
+	}
+	return definition.New()
+}
+
+// NewBlockByKey creates the block with given key, with default state
+func NewBlockByKey(key string) (Block, bool) {
+	definition, exists := blocksByKey[key]
+	if !exists {
+		return nil, false
+	}
+	return definition.New(), true
+}
+
+// Returns the ID of the block with given key
+func BlockTypeByKey(key string) (BlockType, bool) {
+	definition, exists := blocksByKey[key]
+	return definition.Type, exists
+}
+
+// Returns the key, the block type was registered with
+func (t BlockType) Key() string {
+	return blocksByType[t].Key
+}
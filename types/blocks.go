@@ -1,8 +1,10 @@
 package types
 
 import (
+	"fmt"
+
+	"github.com/3elDU/bamboo/world_type"
 	"github.com/google/uuid"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type BlockType int
@@ -32,88 +34,65 @@ const (
 	PitBlock
 	IronOreBlock
 	FurnaceBlock
+	CaveDescentBlock
+	CopperOreBlock
+	GoldOreBlock
+	CoalOreBlock
+
+	// Number of built-in block types, must stay the last one.
+	// Each of them must have a name below, and must be registered, CheckBlockRegistry() makes sure of that
+	builtinBlockTypes
 )
 
-func NewBlock(id BlockType) Block {
-	switch id {
-	case EmptyBlock:
-		return NewEmptyBlock()
-	case StoneBlock:
-		return NewStoneBlock()
-	case WaterBlock:
-		return NewWaterBlock()
-	case SandBlock:
-		return NewSandBlock()
-	case GrassBlock:
-		return NewGrassBlock()
-	case SnowBlock:
-		return NewSnowBlock()
-	case ShortGrassBlock:
-		return NewShortGrassBlock()
-	case TallGrassBlock:
-		return NewTallGrassBlock()
-	case FlowersBlock:
-		return NewFlowersBlock()
-	case PineTreeBlock:
-		return NewPineTreeBlock()
-	case RedMushroomBlock:
-		return NewRedMushroomBlock()
-	case WhiteMushroomBlock:
-		return NewWhiteMushroomBlock()
-	case CaveEntranceBlock:
-		return NewCaveEntranceBlock(uuid.New())
-	case CaveWallBlock:
-		return NewCaveWallBlock()
-	case CaveFloorBlock:
-		return NewCaveFloorBlock(false)
-	case CaveExitBlock:
-		return NewCaveExitBlock()
-	case PineSaplingBlock:
-		return NewPineSaplingBlock()
-	case CampfireBlock:
-		return NewCampfireBlock()
-	case BerryBushBlock:
-		return NewBerryBushBlock(0)
-	case SandWithStonesBlock:
-		return NewSandWithStonesBlock()
-	case SandWithClayBlock:
-		return NewSandWithClayBlock()
-	case PitBlock:
-		return NewPitBlock()
-	case IronOreBlock:
-		return NewIronOreBlock()
-	case FurnaceBlock:
-		return NewFurnaceBlock()
-	}
+var blockTypeNames = [builtinBlockTypes]string{
+	EmptyBlock:          "EmptyBlock",
+	StoneBlock:          "StoneBlock",
+	WaterBlock:          "WaterBlock",
+	SandBlock:           "SandBlock",
+	GrassBlock:          "GrassBlock",
+	SnowBlock:           "SnowBlock",
+	ShortGrassBlock:     "ShortGrassBlock",
+	TallGrassBlock:      "TallGrassBlock",
+	FlowersBlock:        "FlowersBlock",
+	PineTreeBlock:       "PineTreeBlock",
+	RedMushroomBlock:    "RedMushroomBlock",
+	WhiteMushroomBlock:  "WhiteMushroomBlock",
+	CaveEntranceBlock:   "CaveEntranceBlock",
+	CaveWallBlock:       "CaveWallBlock",
+	CaveFloorBlock:      "CaveFloorBlock",
+	CaveExitBlock:       "CaveExitBlock",
+	PineSaplingBlock:    "PineSaplingBlock",
+	CampfireBlock:       "CampfireBlock",
+	BerryBushBlock:      "BerryBushBlock",
+	SandWithStonesBlock: "SandWithStonesBlock",
+	SandWithClayBlock:   "SandWithClayBlock",
+	PitBlock:            "PitBlock",
+	IronOreBlock:        "IronOreBlock",
+	FurnaceBlock:        "FurnaceBlock",
+	CaveDescentBlock:    "CaveDescentBlock",
+	CopperOreBlock:      "CopperOreBlock",
+	GoldOreBlock:        "GoldOreBlock",
+	CoalOreBlock:        "CoalOreBlock",
+}
 
-	return NewEmptyBlock()
+func (t BlockType) String() string {
+	if t >= 0 && t < builtinBlockTypes && blockTypeNames[t] != "" {
+		return blockTypeNames[t]
+	}
+	// blocks defined in data files don't have a name in the enum
+	if key := t.Key(); key != "" {
+		return key
+	}
+	return fmt.Sprintf("BlockType(%d)", int(t))
 }
 
+// Constructors of blocks, which need parameters.
+// Other blocks are created with NewBlock()
 var (
-	NewEmptyBlock          func() Block
-	NewStoneBlock          func() Block
-	NewWaterBlock          func() Block
-	NewSandBlock           func() Block
-	NewGrassBlock          func() Block
-	NewSnowBlock           func() Block
-	NewShortGrassBlock     func() Block
-	NewTallGrassBlock      func() Block
-	NewFlowersBlock        func() Block
-	NewPineTreeBlock       func() Block
-	NewRedMushroomBlock    func() Block
-	NewWhiteMushroomBlock  func() Block
-	NewCaveEntranceBlock   func(uuid uuid.UUID) Block
-	NewCaveWallBlock       func() Block
-	NewCaveFloorBlock      func(hasGrass bool) Block
-	NewCaveExitBlock       func() Block
-	NewPineSaplingBlock    func() Block
-	NewCampfireBlock       func() Block
-	NewBerryBushBlock      func(berries int) Block
-	NewSandWithStonesBlock func() Block
-	NewSandWithClayBlock   func() Block
-	NewPitBlock            func() Block
-	NewIronOreBlock        func() Block
-	NewFurnaceBlock        func() Block
+	NewCaveEntranceBlock func(uuid uuid.UUID) Block
+	NewCaveFloorBlock    func(hasGrass bool) Block
+	NewBerryBushBlock    func(berries int) Block
+	NewCaveDescentBlock  func(uuid uuid.UUID, target world_type.WorldType) Block
 )
 
 type Block interface {
@@ -124,13 +103,38 @@ type Block interface {
 	ParentChunk() Chunk
 	SetParentChunk(chunk Chunk)
 
-	Update(world World)
-
 	State() interface{}
 	// LoadState panicks on error
 	LoadState(interface{})
 }
 
+// Chunks don't update every block every tick, a block has to opt in to be ticked.
+// Most blocks should use scheduled or random ticks, only blocks that really change every tick
+// (like a burning campfire) should implement UpdatableBlock.
+
+// A block that is updated every tick
+type UpdatableBlock interface {
+	Block
+	Update(world World)
+}
+
+// A block that can ask to be ticked after a delay, with Chunk.ScheduleTick()
+type ScheduledTickBlock interface {
+	Block
+	// Number of ticks till the first scheduled tick, when the block is placed or loaded.
+	// 0 means the block doesn't need to be ticked for now.
+	InitialTickDelay() uint64
+	ScheduledTick(world World)
+}
+
+// A block that is ticked from time to time, when the chunk randomly picks it.
+// Each tick, config.RandomTicksPerChunk random blocks are picked in every loaded chunk.
+// Good for slow, random processes, like plant growth.
+type RandomTickBlock interface {
+	Block
+	RandomTick(world World)
+}
+
 // A block that player can collide with
 type CollidableBlock interface {
 	Block
@@ -141,12 +145,19 @@ type CollidableBlock interface {
 }
 
 // A block that can be rendered onto the screen
+// Blocks are drawn by the world renderer (package world/render),
+// so they don't need a graphics context themselves.
 type DrawableBlock interface {
 	Block
-	// recursiveRedraw means that a block can trigger a redraw of other blocks/chunks
-	// if it is set to false, the block shouldn't attempt to trigger redraw of other chunks/blocks
-	Render(world World, screen *ebiten.Image, pos Vec2f, recursiveRedraw bool)
 	TextureName() string
+	// In degrees
+	TextureRotation() float64
+}
+
+// A block, which texture connects to the neighboring blocks
+type ConnectedBlock interface {
+	DrawableBlock
+	ShouldConnect(other BlockType) bool
 }
 
 // A block that reacts to player colliding with it
@@ -176,6 +187,21 @@ type ICampfireBlock interface {
 	IsLitUp() bool
 }
 
+type IFurnaceBlock interface {
+	Block
+	Energy() float64
+	// Adds the item as fuel, or to the items to smelt.
+	// Returns false, if the furnace doesn't accept the item
+	AddItem(item Item) bool
+	InputSlot() *ItemSlot
+	OutputSlot() *ItemSlot
+}
+
+// A block with fire inside, like a campfire or a furnace
+type ILitBlock interface {
+	IsLitUp() bool
+}
+
 // A generic crop block that can run out of water, and can be watered
 type ICropBlock interface {
 	NeedsWatering() bool
@@ -1,19 +1,16 @@
 package types
 
-import (
-	"github.com/hajimehoshi/ebiten/v2"
-)
-
 type Chunk interface {
 	// Returns a dummy block, in case of an error
 	At(x uint, y uint) Block
 	BlockCoords() Vec2u
 	Coords() Vec2u
-	Render(world World)
 	Save(metadata Save)
 	SetBlock(x uint, y uint, block Block)
 	Update(world World)
+	// Ticks the block at given coordinates after delay ticks, see ScheduledTickBlock.
+	// The tick is dropped, if the block is replaced or the chunk is unloaded before that.
+	ScheduleTick(x uint, y uint, delay uint64)
 	TriggerRedraw(recursive bool)
 	MarkAsModified()
-	Texture() *ebiten.Image
 }
@@ -1,27 +1,98 @@
 package types
 
+import "math"
+
 type CraftIngredient struct {
 	Type   ItemType
 	Amount int
 }
 
-// CraftCondition is a function that returns true, if a player is able to craft this
-// For example, to craft some item, we can check if the player is standing near a specific block.
-type CraftCondition func() bool
+// A tool, that isn't consumed by the craft, but loses durability instead
+type CraftTool struct {
+	Type       ItemType
+	Durability int
+}
+
+// CraftStation requires the player to be near one of the blocks.
+// For example, clay can only be shaped near a lit campfire or furnace.
+type CraftStation struct {
+	Blocks []BlockType
+	// Maximum distance to the block, in blocks
+	Distance int
+	// The block must be lit up, see ILitBlock
+	Lit bool
+}
 
 type Craft struct {
 	Name        string
 	Description string
-	Conditions  []CraftCondition
+	// All of the stations must be nearby
+	Stations    []CraftStation
 	Ingredients []CraftIngredient
-	Result      CraftIngredient
+	Tools       []CraftTool
+	Results     []CraftIngredient
+}
+
+// Returns true if the player is near one of the station blocks
+func (station CraftStation) Nearby() bool {
+	position := GetCurrentPlayer().Position()
+	world := GetCurrentWorld()
+	distance := float64(station.Distance)
+
+	minX, maxX := math.Max(position.X-distance, 0), math.Min(position.X+distance, float64(world.Size().X-1))
+	minY, maxY := math.Max(position.Y-distance, 0), math.Min(position.Y+distance, float64(world.Size().Y-1))
+	for x := uint64(minX); x <= uint64(maxX); x++ {
+		for y := uint64(minY); y <= uint64(maxY); y++ {
+			block := world.BlockAt(x, y)
+			if !station.accepts(block) {
+				continue
+			}
+			if lit, ok := block.(ILitBlock); station.Lit && !(ok && lit.IsLitUp()) {
+				continue
+			}
+			return true
+		}
+	}
+
+	return false
+}
+
+func (station CraftStation) accepts(block Block) bool {
+	for _, blockType := range station.Blocks {
+		if block.Type() == blockType {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the inventory slot with the tool, that has enough durability left
+func (tool CraftTool) find() *ItemSlot {
+	inventory := GetPlayerInventory()
+	for i := 0; i < inventory.Length(); i++ {
+		slot := inventory.At(i)
+		if slot.Empty || slot.Item.Type() != tool.Type {
+			continue
+		}
+		if durable, ok := slot.Item.(IDurableItem); ok && durable.Durability() >= tool.Durability {
+			return slot
+		}
+	}
+	return nil
+}
+
+func (craft Craft) resultSlots() []ItemSlot {
+	slots := make([]ItemSlot, 0, len(craft.Results))
+	for _, result := range craft.Results {
+		slots = append(slots, NewItemSlot(NewItem(result.Type), uint8(result.Amount)))
+	}
+	return slots
 }
 
 // Returns true if the player is able to craft this item
 func (craft Craft) AbleToCraft() bool {
-	// Check if all craft conditions all met
-	for _, condition := range craft.Conditions {
-		if !condition() {
+	for _, station := range craft.Stations {
+		if !station.Nearby() {
 			return false
 		}
 	}
@@ -32,11 +103,13 @@ func (craft Craft) AbleToCraft() bool {
 			return false
 		}
 	}
+	for _, tool := range craft.Tools {
+		if tool.find() == nil {
+			return false
+		}
+	}
 
-	return GetPlayerInventory().CanAddItem(ItemSlot{
-		Item:     NewItem(craft.Result.Type),
-		Quantity: uint8(craft.Result.Amount),
-	})
+	return GetPlayerInventory().CanAddItems(craft.resultSlots()...)
 }
 
 // Returns true if the item was successfully crafted
@@ -52,10 +125,15 @@ func (craft Craft) Craft() bool {
 		}
 	}
 
-	GetPlayerInventory().AddItem(ItemSlot{
-		Item:     NewItem(craft.Result.Type),
-		Quantity: uint8(craft.Result.Amount),
-	})
+	for _, tool := range craft.Tools {
+		slot := tool.find()
+		if slot.Item.(IDurableItem).Damage(tool.Durability) {
+			// the tool is broken
+			slot.RemoveItem(1)
+		}
+	}
+
+	GetPlayerInventory().AddItems(craft.resultSlots()...)
 
 	return true
 }
@@ -10,10 +10,69 @@ type WorldGenerator interface {
 	GenerateDummy(chunk Chunk)
 	// Returns generated chunks, if any
 	Receive() []Chunk
+	// Drops the generation request, e.g. when the chunk was unloaded before it was generated
+	Cancel(cx, cy uint64)
+	// Requests closest to the given point (in chunk coordinates) are generated first
+	SetPriorityCenter(center Vec2u)
 
-	// Runs a generator main loop.
-	// Must be called with `go Run()`
+	// Starts generation workers in the background
 	Run()
+	// Stops generation workers, when the world is unloaded
+	Stop()
 
 	Seed() int64
+	// Returns the name of the biome at those block coordinates, or an empty string, if the world has no biomes
+	BiomeAt(bx, by uint64) string
+}
+
+// Parameters of the overworld generator.
+// The preset is stored in the save, when the world is created,
+// so the world keeps generating the same way, even if the preset file changes later.
+type WorldgenPreset struct {
+	Name string `json:"name"`
+
+	// Base height noise
+	NoiseScale   float64 `json:"noise_scale"`
+	NoiseAlpha   float64 `json:"noise_alpha"`
+	NoiseBeta    float64 `json:"noise_beta"`
+	NoiseOctaves int32   `json:"noise_octaves"`
+	// Base height is multiplied by it, 0 makes the terrain completely flat
+	HeightAmplitude float64 `json:"height_amplitude"`
+
+	// Uses base height.
+	// Height, below which water will generate
+	WaterHeight float64 `json:"water_height"`
+	// Height, below which sand will generate
+	SandHeight float64 `json:"sand_height"`
+
+	// Circular mask, that surrounds the world with ocean (see WorldShape).
+	// Radius of the mask is the average side of the world (or of the archipelago cell), divided by MaskRadius,
+	// and MaskFalloff makes the mask weaker towards the edges
+	MaskRadius  float64 `json:"mask_radius"`
+	MaskFalloff float64 `json:"mask_falloff"`
+
+	// Scale of temperature and moisture noise
+	BiomeScale float64 `json:"biome_scale"`
+	// Added to empty and foliage heights of every biome, positive values make less trees
+	FoliageHeightOffset float64 `json:"foliage_height_offset"`
+	// Foliage of each biome, by biome name. Biomes that aren't listed don't have any.
+	// nil for worlds created before it was a part of the preset, they use the default foliage
+	FoliageChances map[string]FoliageChances `json:"foliage_chances"`
+	// Uses base height.
+	// Height, above which rivers can start
+	RiverSourceHeight float64 `json:"river_source_height"`
+	// %Chance that a suitable region will have a river
+	RiverChance float64 `json:"river_chance"`
+	// %Chances of spawning structures in their regions, by structure name.
+	// Structures that aren't listed use their default chance
+	StructureChances map[string]float64 `json:"structure_chances"`
+}
+
+// %Chances of generating foliage, inside the foliage zone of a biome.
+// Chances are stacked on top of each other in this order, so they don't overlap
+type FoliageChances struct {
+	BerryBush float64 `json:"berry_bush"`
+	Mushroom  float64 `json:"mushroom"`
+	Flower    float64 `json:"flower"`
+	TallGrass float64 `json:"tall_grass"`
 }
@@ -1,5 +1,11 @@
 package types
 
+import (
+	"bytes"
+	"encoding/gob"
+	"log"
+)
+
 var currentInventory Inventory
 
 // Sets the current instance of Inventory
@@ -40,8 +46,77 @@ type Inventory interface {
 type SavedSlot struct {
 	Empty    bool
 	Quantity uint8
+	// Numeric ID is only valid for the version of the game that saved the slot.
+	// Slots saved before keys were introduced have only the ID.
 	ItemType ItemType
-	State    interface{}
+	ItemKey  string
+	// Slots saved before save format version 3 keep the state here.
+	// If the type of the state isn't registered with gob, the whole inventory can't be decoded.
+	State interface{}
+	// Gob-encoded state. Each slot is decoded on its own,
+	// so an item, which state can't be decoded, is replaced with a placeholder.
+	RawState []byte
+}
+
+// Item state, that couldn't be decoded, because its type isn't registered.
+// Placeholders keep it, so it is written back unchanged on save.
+type UndecodedState []byte
+
+func encodeItemState(state interface{}) []byte {
+	if undecoded, ok := state.(UndecodedState); ok {
+		return undecoded
+	}
+	if state == nil {
+		return nil
+	}
+
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(&state); err != nil {
+		log.Panicf("failed to encode item state %T - %v", state, err)
+	}
+	return buf.Bytes()
+}
+
+// DecodeState decodes RawState into State. Does nothing, if it was already decoded.
+// If the state can't be decoded, it is kept as UndecodedState.
+func (savedSlot *SavedSlot) DecodeState() {
+	if savedSlot.RawState == nil {
+		return
+	}
+
+	var state interface{}
+	if err := gob.NewDecoder(bytes.NewReader(savedSlot.RawState)).Decode(&state); err != nil {
+		log.Printf("SavedSlot.DecodeState() - failed to decode state of %q, keeping it as a placeholder: %v", savedSlot.Key(), err)
+		state = UndecodedState(savedSlot.RawState)
+	}
+	savedSlot.State = state
+	savedSlot.RawState = nil
+}
+
+// Returns the key of the saved item
+func (savedSlot *SavedSlot) Key() string {
+	if savedSlot.ItemKey == "" {
+		return legacyItemKey(savedSlot.ItemType)
+	}
+	return savedSlot.ItemKey
+}
+
+// Returns the item stored in the slot.
+// Items with unknown keys, or state that can't be decoded, are replaced with a placeholder, that keeps their state.
+func (savedSlot *SavedSlot) Item() Item {
+	savedSlot.DecodeState()
+
+	key := savedSlot.Key()
+	item, exists := NewItemByKey(key)
+	if !exists {
+		log.Printf("SavedSlot.Item() - unknown item %q, keeping it as a placeholder", key)
+		return NewUnknownItem(key, savedSlot.State)
+	}
+	if _, undecoded := savedSlot.State.(UndecodedState); undecoded {
+		return NewUnknownItem(key, savedSlot.State)
+	}
+	item.LoadState(savedSlot.State)
+	return item
 }
 
 func (savedSlot *SavedSlot) Load() ItemSlot {
@@ -49,9 +124,8 @@ func (savedSlot *SavedSlot) Load() ItemSlot {
 		return ItemSlot{Empty: true}
 	}
 
-	slot := ItemSlot{}
-	slot.Item = NewItem(savedSlot.ItemType)
-	slot.Quantity = savedSlot.Quantity
-	slot.Item.LoadState(savedSlot.State)
-	return slot
+	return ItemSlot{
+		Item:     savedSlot.Item(),
+		Quantity: savedSlot.Quantity,
+	}
 }
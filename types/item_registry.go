@@ -0,0 +1,164 @@
+package types
+
+import (
+	"encoding/gob"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Everything the game needs to know to create an item.
+// Each item implementation registers its definition from init().
+type ItemDefinition struct {
+	Type ItemType
+	// Stable name of the item, like "bamboo:stick".
+	// Saves refer to items by key, so it must never change, while the numeric ID can.
+	Key string
+	// Creates the item with default state
+	New func() Item
+	// Value of the type returned from Item.State(), it is registered with gob.
+	// Can be nil, if the item doesn't have its own state type.
+	State interface{}
+}
+
+var (
+	itemsByType = make(map[ItemType]ItemDefinition)
+	itemsByKey  = make(map[string]ItemDefinition)
+
+	// Items defined in data files, that Go code doesn't refer to, get IDs after the built-in ones
+	nextItemType = ItemType(len(itemTypeNames))
+)
+
+// RegisterItem adds the item to the registry. Panics on duplicate IDs and keys.
+// Must be called from init() of the item implementation.
+func RegisterItem(definition ItemDefinition) {
+	if definition.New == nil {
+		log.Panicf("RegisterItem() - item %v has no constructor", definition.Type)
+	}
+	if !strings.Contains(definition.Key, ":") {
+		log.Panicf("RegisterItem() - key %q of item %v must be namespaced, like \"bamboo:stick\"", definition.Key, definition.Type)
+	}
+	if existing, exists := itemsByType[definition.Type]; exists {
+		log.Panicf("RegisterItem() - item %v is already registered with key %q", definition.Type, existing.Key)
+	}
+	if existing, exists := itemsByKey[definition.Key]; exists {
+		log.Panicf("RegisterItem() - key %q is already taken by item %v", definition.Key, existing.Type)
+	}
+
+	if definition.State != nil {
+		gob.Register(definition.State)
+	}
+
+	itemsByType[definition.Type] = definition
+	itemsByKey[definition.Key] = definition
+}
+
+// CheckItemRegistry panics, if any of the built-in item types is not registered.
+// Called on startup, after all item implementations are imported.
+func CheckItemRegistry() {
+	for id, definition := range itemsByType {
+		if id >= nextItemType {
+			log.Panicf("CheckItemRegistry() - %q is registered with unknown item type %v", definition.Key, uint(id))
+		}
+	}
+
+	missing := make([]string, 0)
+	for id := range itemTypeNames {
+		if _, exists := itemsByType[ItemType(id)]; !exists {
+			missing = append(missing, ItemType(id).String())
+		}
+	}
+	if len(missing) > 0 {
+		log.Panicf("CheckItemRegistry() - items are not registered: %v", strings.Join(missing, ", "))
+	}
+}
+
+// AllocateItemType returns a new ID for an item, that isn't part of the ItemType enum
+func AllocateItemType() ItemType {
+	id := nextItemType
+	nextItemType++
+	return id
+}
+
+// Returns the built-in item type by its name in the enum, like "StickItem"
+func ItemTypeByName(name string) (ItemType, bool) {
+	for id, typeName := range itemTypeNames {
+		if typeName == name {
+			return ItemType(id), true
+		}
+	}
+	return 0, false
+}
+
+// NewItem creates the item with given ID, with default state
+func NewItem(id ItemType) Item {
+	definition, exists := itemsByType[id]
+	if !exists {
+		log.Panicf("NewItem() - item %v is not registered", id)
+	}
+	return definition.New()
+}
+
+// NewItemByKey creates the item with given key, with default state
+func NewItemByKey(key string) (Item, bool) {
+	definition, exists := itemsByKey[key]
+	if !exists {
+		return nil, false
+	}
+	return definition.New(), true
+}
+
+// Returns the ID of the item with given key
+func ItemTypeByKey(key string) (ItemType, bool) {
+	definition, exists := itemsByKey[key]
+	return definition.Type, exists
+}
+
+// Returns the key, the item type was registered with
+func (t ItemType) Key() string {
+	return itemsByType[t].Key
+}
+
+// Keys of the items, in the order of the numeric IDs they had before keys were saved.
+// Slots from older versions only have the IDs, so this list must never be changed.
+var legacyItemKeys = [...]string{
+	"bamboo:block",
+	"bamboo:test",
+	"bamboo:pine_sapling",
+	"bamboo:stick",
+	"bamboo:flint",
+	"bamboo:berry",
+	"bamboo:clay",
+	"bamboo:watering_can",
+	"bamboo:clay_shovel",
+	"bamboo:raw_iron",
+	"bamboo:iron_ingot",
+	"bamboo:clay_pickaxe",
+	"bamboo:raw_copper",
+	"bamboo:copper_ingot",
+	"bamboo:raw_gold",
+	"bamboo:gold_ingot",
+	"bamboo:coal",
+	"bamboo:gold_pickaxe",
+	"bamboo:copper_pickaxe",
+	"bamboo:iron_pickaxe",
+}
+
+// Returns the key of the item, that had given numeric ID before keys were saved
+func legacyItemKey(id ItemType) string {
+	if int(id) >= len(legacyItemKeys) {
+		return fmt.Sprintf("legacy:%v", uint(id))
+	}
+	return legacyItemKeys[id]
+}
+
+// A placeholder for an item, which key isn't registered.
+// For example, when the item was removed from the game, or the save comes from a newer version.
+// The placeholder keeps the original key and state, so they are written back on save.
+type IUnknownItem interface {
+	Item
+	OriginalKey() string
+}
+
+// Creates the placeholder, that keeps given key and state
+var NewUnknownItem func(key string, state interface{}) Item
@@ -26,7 +26,7 @@ func (slot *ItemSlot) CanAddItem(other ItemSlot) bool {
 		return true
 	}
 
-	if slot.Item.Type() != other.Item.Type() {
+	if slot.Item.Type() != other.Item.Type() || !slot.Item.Stackable() {
 		return false
 	}
 
@@ -48,7 +48,7 @@ func (slot *ItemSlot) AddItem(other ItemSlot) bool {
 		return true
 	}
 
-	if other.Item.Type() != slot.Item.Type() {
+	if other.Item.Type() != slot.Item.Type() || !slot.Item.Stackable() {
 		return false
 	}
 
@@ -73,17 +73,17 @@ func (slot *ItemSlot) RemoveItem(count uint8) {
 }
 
 func (slot *ItemSlot) Save() SavedSlot {
-	var itemType ItemType
-	var itemState interface{}
-	if !slot.Empty {
-		itemType = slot.Item.Type()
-		itemState = slot.Item.State()
-	}
-
-	return SavedSlot{
+	saved := SavedSlot{
 		Empty:    slot.Empty,
 		Quantity: slot.Quantity,
-		ItemType: itemType,
-		State:    itemState,
 	}
+	if !slot.Empty {
+		saved.ItemType = slot.Item.Type()
+		saved.ItemKey = slot.Item.Type().Key()
+		saved.RawState = encodeItemState(slot.Item.State())
+		if unknown, ok := slot.Item.(IUnknownItem); ok {
+			saved.ItemKey = unknown.OriginalKey()
+		}
+	}
+	return saved
 }
@@ -1,6 +1,8 @@
 package types
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"fmt"
+)
 
 // The tool family that the item belongs to
 type ToolFamily int
@@ -14,7 +16,29 @@ const (
 	ToolFamilyScissors
 )
 
-// Represents "Hardness" of a material
+var toolFamilyNames = [...]string{
+	ToolFamilyNone:     "none",
+	ToolFamilySword:    "sword",
+	ToolFamilyPickaxe:  "pickaxe",
+	ToolFamilyAxe:      "axe",
+	ToolFamilyShovel:   "shovel",
+	ToolFamilyScissors: "scissors",
+}
+
+// Parses tool family from its name, so it can be written as "pickaxe" in JSON
+func (f *ToolFamily) UnmarshalText(text []byte) error {
+	for family, name := range toolFamilyNames {
+		if name == string(text) {
+			*f = ToolFamily(family)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown tool family %q", text)
+}
+
+// Represents "Hardness" of a material.
+// Ores need a pickaxe made from the previous material:
+// clay pickaxe mines gold, gold pickaxe mines copper, copper pickaxe mines iron.
 type ToolStrength int
 
 const (
@@ -26,11 +50,31 @@ const (
 	ToolStrengthIron                         // 128
 )
 
+var toolStrengthNames = [...]string{
+	ToolStrengthBareHand: "bare_hand",
+	ToolStrengthWood:     "wood",
+	ToolStrengthClay:     "clay",
+	ToolStrengthGold:     "gold",
+	ToolStrengthCopper:   "copper",
+	ToolStrengthIron:     "iron",
+}
+
+// Parses tool strength from its name, so it can be written as "copper" in JSON
+func (s *ToolStrength) UnmarshalText(text []byte) error {
+	for strength, name := range toolStrengthNames {
+		if name == string(text) {
+			*s = ToolStrength(strength)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown tool strength %q", text)
+}
+
 type ItemType uint
 
 const (
-	BlockItem ItemType = iota // Kept for backwards compatibility of IDs
-	TestItem                  // Kept for backwards compatibility of IDs
+	// Placeholder for items, which keys are not registered
+	UnknownItem ItemType = iota
 	PineSaplingItem
 	StickItem
 	FlintItem
@@ -41,53 +85,54 @@ const (
 	RawIronItem
 	IronIngotItem
 	ClayPickaxeItem
+	RawCopperItem
+	CopperIngotItem
+	RawGoldItem
+	GoldIngotItem
+	CoalItem
+	GoldPickaxeItem
+	CopperPickaxeItem
+	IronPickaxeItem
 )
 
-func NewItem(id ItemType) Item {
-	switch id {
-	case PineSaplingItem:
-		return NewPineSaplingItem()
-	case StickItem:
-		return NewStickItem()
-	case FlintItem:
-		return NewFlintItem()
-	case BerryItem:
-		return NewBerryItem()
-	case ClayItem:
-		return NewClayItem()
-	case WateringCanItem:
-		return NewWateringCanItem()
-	case ClayShovelItem:
-		return NewClayShovelItem()
-	case RawIronItem:
-		return NewRawIronItem()
-	case IronIngotItem:
-		return NewIronIngotItem()
-	case ClayPickaxeItem:
-		return NewClayPickaxeItem()
-	}
-
-	return nil
+var itemTypeNames = [...]string{
+	UnknownItem:       "UnknownItem",
+	PineSaplingItem:   "PineSaplingItem",
+	StickItem:         "StickItem",
+	FlintItem:         "FlintItem",
+	BerryItem:         "BerryItem",
+	ClayItem:          "ClayItem",
+	WateringCanItem:   "WateringCanItem",
+	ClayShovelItem:    "ClayShovelItem",
+	RawIronItem:       "RawIronItem",
+	IronIngotItem:     "IronIngotItem",
+	ClayPickaxeItem:   "ClayPickaxeItem",
+	RawCopperItem:     "RawCopperItem",
+	CopperIngotItem:   "CopperIngotItem",
+	RawGoldItem:       "RawGoldItem",
+	GoldIngotItem:     "GoldIngotItem",
+	CoalItem:          "CoalItem",
+	GoldPickaxeItem:   "GoldPickaxeItem",
+	CopperPickaxeItem: "CopperPickaxeItem",
+	IronPickaxeItem:   "IronPickaxeItem",
 }
 
-var (
-	NewPineSaplingItem func() Item
-	NewStickItem       func() Item
-	NewFlintItem       func() Item
-	NewBerryItem       func() Item
-	NewClayItem        func() Item
-	NewWateringCanItem func() Item
-	NewClayShovelItem  func() Item
-	NewRawIronItem     func() Item
-	NewIronIngotItem   func() Item
-	NewClayPickaxeItem func() Item
-)
+func (t ItemType) String() string {
+	if int(t) < len(itemTypeNames) {
+		return itemTypeNames[t]
+	}
+	// items defined in data files don't have a name in the enum
+	if key := t.Key(); key != "" {
+		return key
+	}
+	return fmt.Sprintf("ItemType(%d)", uint(t))
+}
 
 type Item interface {
 	Name() string
 	Description() string
 
-	Texture() *ebiten.Image
+	TextureName() string
 	Type() ItemType
 	Stackable() bool
 
@@ -102,6 +147,15 @@ type IToolItem interface {
 	UseTool(pos Vec2u)
 }
 
+// A tool that wears out when used
+type IDurableItem interface {
+	Item
+	Durability() int
+	MaxDurability() int
+	// Damage reduces durability of the item, and returns true if the item is broken
+	Damage(amount int) bool
+}
+
 // An item that produces energy by burning
 type IBurnableItem interface {
 	BurningEnergy() float64
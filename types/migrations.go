@@ -0,0 +1,80 @@
+package types
+
+import (
+	"log"
+	"sort"
+
+	"github.com/3elDU/bamboo/config"
+)
+
+// A single upgrade step, that rewrites state of a block or an item saved by older version of the game
+type migration struct {
+	// Save format version that this step upgrades to.
+	// The step is applied to everything saved with version lower than this.
+	version int
+	migrate func(state interface{}) interface{}
+}
+
+var (
+	blockMigrations = make(map[BlockType][]migration)
+	itemMigrations  = make(map[ItemType][]migration)
+)
+
+func addMigration(steps []migration, version int, migrate func(interface{}) interface{}) []migration {
+	if version < 1 || version > config.SaveFormatVersion {
+		log.Panicf("migration to version %v is outside of supported range 1..%v", version, config.SaveFormatVersion)
+	}
+
+	steps = append(steps, migration{version: version, migrate: migrate})
+	// keep the steps ordered, so they are applied one after another
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].version < steps[j].version
+	})
+	return steps
+}
+
+// RegisterBlockMigration registers an upgrade step for the state of given block type.
+// Must be called from init() of the block implementation.
+func RegisterBlockMigration(version int, blockType BlockType, migrate func(state interface{}) interface{}) {
+	blockMigrations[blockType] = addMigration(blockMigrations[blockType], version, migrate)
+}
+
+// RegisterItemMigration registers an upgrade step for the state of given item type.
+// Must be called from init() of the item implementation.
+func RegisterItemMigration(version int, itemType ItemType, migrate func(state interface{}) interface{}) {
+	itemMigrations[itemType] = addMigration(itemMigrations[itemType], version, migrate)
+}
+
+func applyMigrations(steps []migration, savedVersion int, state interface{}) interface{} {
+	for _, step := range steps {
+		if step.version > savedVersion {
+			state = step.migrate(state)
+		}
+	}
+	return state
+}
+
+// MigrateBlockState upgrades block state, saved with the given save format version, to the current one
+func MigrateBlockState(savedVersion int, blockType BlockType, state interface{}) interface{} {
+	return applyMigrations(blockMigrations[blockType], savedVersion, state)
+}
+
+// MigrateItemState upgrades item state, saved with the given save format version, to the current one
+func MigrateItemState(savedVersion int, itemType ItemType, state interface{}) interface{} {
+	return applyMigrations(itemMigrations[itemType], savedVersion, state)
+}
+
+// Migrate upgrades item state in the slot, saved with the given save format version, to the current one
+func (savedSlot *SavedSlot) Migrate(savedVersion int) {
+	if savedSlot.Empty {
+		return
+	}
+	savedSlot.DecodeState()
+	// unknown items are kept as they are
+	if _, undecoded := savedSlot.State.(UndecodedState); undecoded {
+		return
+	}
+	if itemType, exists := ItemTypeByKey(savedSlot.Key()); exists {
+		savedSlot.State = MigrateItemState(savedVersion, itemType, savedSlot.State)
+	}
+}
@@ -0,0 +1,25 @@
+package types
+
+import "log"
+
+// Checks of references between registries, like a block dropping an item, that must exist
+var registryChecks []func() error
+
+// AddRegistryCheck adds a check, that runs after all blocks and items are registered.
+// Must be called from init().
+func AddRegistryCheck(check func() error) {
+	registryChecks = append(registryChecks, check)
+}
+
+// CheckRegistries panics, if any of the built-in blocks or items is not registered,
+// or if any of the registered checks fails. Called on startup.
+func CheckRegistries() {
+	CheckBlockRegistry()
+	CheckItemRegistry()
+
+	for _, check := range registryChecks {
+		if err := check(); err != nil {
+			log.Panicf("CheckRegistries() - %v", err)
+		}
+	}
+}
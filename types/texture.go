@@ -1,20 +1,8 @@
 package types
 
-import (
-	"github.com/hajimehoshi/ebiten/v2"
-)
-
+// A texture from the assets, that is known to exist.
+// Blocks and items only refer to textures by name, so they can be used without a graphics context.
+// The images themselves are uploaded to the GPU by package textures.
 type Texture interface {
-	Texture() *ebiten.Image
-	Name() string
-	// Returns size of the texture, multiplied by ui scaling
-	// Useful for UI elements
-	ScaledSize() (float64, float64)
-}
-
-type ConnectedTexture interface {
-	Texture() *ebiten.Image
-	ConnectedSides() [4]bool
-	SetConnectedSides(sides [4]bool)
 	Name() string
 }
@@ -3,7 +3,6 @@ package types
 import (
 	"github.com/3elDU/bamboo/world_type"
 	"github.com/google/uuid"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 var currentWorld World
@@ -42,7 +41,6 @@ type World interface {
 	// Returns world generator associated with this world
 	Generator() WorldGenerator
 	Metadata() Save
-	Render(screen *ebiten.Image, playerX float64, playerY float64, scaling float64)
 	Save()
 	Seed() int64
 	// Returned size is in chunks
@@ -52,6 +50,10 @@ type World interface {
 
 // Structure with metadata, representing a world save
 type Save struct {
+	// Save format version the world was saved with.
+	// Worlds saved before the versioning was introduced have version 0
+	Version int
+
 	Name string // world name as from the user
 	// BaseUUID is a base uuid for all worlds, as well as name of the base save folder.
 	// All subsequent worlds will be created in their own directories, under the base directory.
@@ -60,8 +62,61 @@ type Save struct {
 	UUID uuid.UUID
 	Seed int64
 	// Size of the world in blocks
-	Size      Vec2u
+	Size Vec2u
+	// Shape of the land. Only used by the overworld
+	Shape     WorldShape
 	WorldType world_type.WorldType
 	// Player's spawn point
 	SpawnPoint Vec2u
+	// Preset the world was generated with.
+	// nil for worlds created before presets were introduced, they use the default preset
+	Preset *WorldgenPreset
+	// Name of the codec, that newly saved chunks are compressed with.
+	// Empty for worlds created before it was selectable, they use config.ChunkCodec
+	Codec string
+}
+
+// Shape of the land in the world, which is formed by the mask, that surrounds the world with ocean
+type WorldShape int
+
+const (
+	// One big island in the middle of the world.
+	// Worlds created before shapes were introduced have this shape
+	SingleIslandShape WorldShape = iota
+	// Grid of smaller islands, separated by the ocean
+	ArchipelagoShape
+	// Land stretches almost to the edges of the world, with the ocean only around the border
+	ContinentShape
+)
+
+// Archipelago is split into a grid of IslandGrid x IslandGrid cells, with an island in each cell
+const IslandGrid = 3
+
+var worldShapeNames = [...]string{
+	SingleIslandShape: "single island",
+	ArchipelagoShape:  "archipelago",
+	ContinentShape:    "continent",
+}
+
+func (s WorldShape) String() string {
+	if s < 0 || int(s) >= len(worldShapeNames) {
+		return "unknown"
+	}
+	return worldShapeNames[s]
+}
+
+// Returns the area (in blocks), where the player can spawn in the world of that size.
+// It is the middle of the island in the center of the world
+func (s WorldShape) SpawnArea(worldSize Vec2u) (min, max Vec2u) {
+	areaOrigin, areaSize := Vec2u{}, worldSize
+	if s == ArchipelagoShape {
+		// only the central island
+		areaSize = Vec2u{X: worldSize.X / IslandGrid, Y: worldSize.Y / IslandGrid}
+		areaOrigin = Vec2u{X: areaSize.X * (IslandGrid / 2), Y: areaSize.Y * (IslandGrid / 2)}
+	}
+
+	// from 1/4 of the area to 3/4 of it
+	min = Vec2u{X: areaOrigin.X + areaSize.X/4, Y: areaOrigin.Y + areaSize.Y/4}
+	max = Vec2u{X: areaOrigin.X + areaSize.X/4*3, Y: areaOrigin.Y + areaSize.Y/4*3}
+	return
 }
@@ -1,10 +1,13 @@
 package ui
 
 import (
+	"fmt"
 	"math"
 
-	"github.com/3elDU/bamboo/assets"
+	"github.com/3elDU/bamboo/colors"
 	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/font"
+	"github.com/3elDU/bamboo/textures"
 	"github.com/3elDU/bamboo/types"
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -13,6 +16,8 @@ type CompassComponent struct {
 	baseComponent
 
 	angleRad float64
+	// How deep underground the player is, 0 in the overworld
+	depth int
 
 	compassTexture *ebiten.Image
 	arrowTexture   *ebiten.Image
@@ -23,8 +28,8 @@ func NewCompassComponent() *CompassComponent {
 	return &CompassComponent{
 		baseComponent: newBaseComponent(),
 
-		compassTexture: assets.Texture("compass").Texture(),
-		arrowTexture:   assets.Texture("compass_arrow").Texture(),
+		compassTexture: textures.Texture("compass"),
+		arrowTexture:   textures.Texture("compass_arrow"),
 		opts:           &ebiten.DrawImageOptions{},
 	}
 }
@@ -54,6 +59,8 @@ func (compass *CompassComponent) Update() error {
 	deltaY := int(spawnpoint.Y) - int(playerPosition.Y)
 	compass.angleRad = math.Atan2(float64(deltaY), float64(deltaX))
 
+	compass.depth = types.GetCurrentWorld().Metadata().WorldType.Depth()
+
 	return nil
 }
 func (compass *CompassComponent) Draw(screen *ebiten.Image, x, y float64) error {
@@ -74,5 +81,13 @@ func (compass *CompassComponent) Draw(screen *ebiten.Image, x, y float64) error
 	compass.opts.GeoM.Translate(x, y)
 	screen.DrawImage(compass.arrowTexture, compass.opts)
 
+	// Draw the depth in the bottom-right corner
+	if compass.depth > 0 {
+		depth := fmt.Sprintf("-%v", compass.depth)
+		width, height := compass.ComputedSize()
+		textWidth, textHeight := font.GetStringSize(depth, 1)
+		font.RenderFont(screen, depth, x+width-textWidth, y+height-textHeight, colors.C("white"))
+	}
+
 	return nil
 }
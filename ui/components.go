@@ -13,7 +13,6 @@ import (
 
 	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/font"
-	"github.com/3elDU/bamboo/types"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
@@ -618,11 +617,11 @@ func BackgroundImage(mode BackgroundImageRenderingMode, texture *ebiten.Image, c
 	child.SetParent(bg)
 	return bg
 }
-func TileBackgroundImage(texture types.Texture, child Component) *BackgroundImageComponent {
-	return BackgroundImage(BackgroundTile, texture.Texture(), child)
+func TileBackgroundImage(texture *ebiten.Image, child Component) *BackgroundImageComponent {
+	return BackgroundImage(BackgroundTile, texture, child)
 }
-func StretchBackgroundImage(texture types.Texture, child Component) *BackgroundImageComponent {
-	return BackgroundImage(BackgroundStretch, texture.Texture(), child)
+func StretchBackgroundImage(texture *ebiten.Image, child Component) *BackgroundImageComponent {
+	return BackgroundImage(BackgroundStretch, texture, child)
 }
 
 func (b *BackgroundImageComponent) MaxSize() (float64, float64) {
@@ -1,9 +1,11 @@
 package ui
 
 import (
+	"fmt"
 	"image/color"
 
 	"github.com/3elDU/bamboo/colors"
+	"github.com/3elDU/bamboo/textures"
 	"github.com/3elDU/bamboo/types"
 )
 
@@ -71,6 +73,17 @@ func NewCraftDescription() *CraftDescription {
 	return craftDescription
 }
 
+// A row with item texture, amount and name
+func craftItemRow(itemType types.ItemType, amount string) Component {
+	item := types.NewItem(itemType)
+	return HStack(
+		Tooltip(Image(textures.Texture(item.TextureName()))).
+			WithNeutralColor(),
+		Label(amount),
+		Label(item.Name()),
+	).WithSpacing(1).AlignChildren(AlignCenter)
+}
+
 func (craftDescription *CraftDescription) SetCraft(craft types.Craft) {
 	craftDescription.craft = craft
 
@@ -78,28 +91,38 @@ func (craftDescription *CraftDescription) SetCraft(craft types.Craft) {
 		Label("Ingredients:"),
 	)
 	for _, ingredient := range craft.Ingredients {
-		ingredientsStack.AddChild(HStack(
-			Tooltip(Image(types.NewItem(ingredient.Type).Texture())).
+		ingredientsStack.AddChild(craftItemRow(ingredient.Type, fmt.Sprintf("%vx", ingredient.Amount)))
+	}
+	for _, tool := range craft.Tools {
+		ingredientsStack.AddChild(craftItemRow(tool.Type, fmt.Sprintf("-%v durability", tool.Durability)))
+	}
+
+	resultsStack := VStack().WithSpacing(0.2).WithChildren(
+		Label("Results:"),
+	)
+	for _, result := range craft.Results {
+		resultsStack.AddChild(craftItemRow(result.Type, fmt.Sprintf("%vx", result.Amount)))
+	}
+
+	// The recipe is shown with the texture of its first result
+	header := HStack(Label(craft.Name))
+	if len(craft.Results) > 0 {
+		header = HStack(
+			Tooltip(Image(textures.Texture(types.NewItem(craft.Results[0].Type).TextureName()))).
 				WithNeutralColor(),
-			LabelF("%vx", ingredient.Amount),
-			Label(types.NewItem(ingredient.Type).Name()),
-		).WithSpacing(1).AlignChildren(AlignCenter))
+			Label(craft.Name),
+		).WithSpacing(1).AlignChildren(AlignCenter)
 	}
 
 	craftDescription.StyledComponent = Styled(
 		Padding(0.3, VStack().WithSpacing(1.0).WithChildren(
-			// Header
-			HStack(
-				Tooltip(Image(types.NewItem(craft.Result.Type).Texture())).
-					WithNeutralColor(),
-				Label(types.NewItem(craft.Result.Type).Name()),
-			).WithSpacing(1).AlignChildren(AlignCenter),
+			header,
 
 			// Description
 			Label(craft.Description),
 
-			// Ingredients
 			ingredientsStack,
+			resultsStack,
 		)),
 	).WithTextColor(color.White)
 }
@@ -118,3 +118,11 @@ func (f *FormComponent) Update() error {
 func (f *FormComponent) Draw(screen *ebiten.Image, x, y float64) error {
 	return f.view.Draw(screen, x, y)
 }
+
+// Returns what is currently typed into the prompt
+func (f *FormComponent) Input(prompt int) string {
+	return f.prompts[prompt].input.Input()
+}
+func (f *FormComponent) SetInput(prompt int, input string) {
+	f.prompts[prompt].input.SetInput(input)
+}
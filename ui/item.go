@@ -3,7 +3,7 @@ package ui
 import (
 	"fmt"
 
-	"github.com/3elDU/bamboo/assets"
+	"github.com/3elDU/bamboo/textures"
 	"github.com/3elDU/bamboo/types"
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -24,11 +24,11 @@ func ItemSlot(slot *types.ItemSlot) *ItemSlotComponent {
 	var overlay *OverlayComponent
 	if slot.Empty {
 		overlay = Overlay(
-			Image(assets.Texture("empty").Texture()),
+			Image(textures.Texture("empty")),
 		)
 	} else {
 		overlay = Overlay(
-			Image(slot.Item.Texture()),
+			Image(textures.Texture(slot.Item.TextureName())),
 			Label(itemCountLabel),
 		)
 	}
@@ -3,10 +3,10 @@ package ui
 import (
 	"image"
 
-	"github.com/3elDU/bamboo/assets"
 	"github.com/3elDU/bamboo/colors"
 	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/font"
+	"github.com/3elDU/bamboo/textures"
 	"github.com/3elDU/bamboo/types"
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -87,12 +87,12 @@ func AssembleTooltipTexture(texture *ebiten.Image) TooltipTexture {
 }
 
 func init() {
-	tooltip = AssembleTooltipTexture(assets.Texture("tooltip").Texture())
-	tooltip_button = AssembleTooltipTexture(assets.Texture("tooltip_button").Texture())
-	tooltip_button_hover = AssembleTooltipTexture(assets.Texture("tooltip_button_hover").Texture())
-	tooltip_input = AssembleTooltipTexture(assets.Texture("tooltip_input").Texture())
-	tooltip_input_focused = AssembleTooltipTexture(assets.Texture("tooltip_input_focused").Texture())
-	tooltip_neutral = AssembleTooltipTexture(assets.Texture("tooltip_neutral").Texture())
+	tooltip = AssembleTooltipTexture(textures.Texture("tooltip"))
+	tooltip_button = AssembleTooltipTexture(textures.Texture("tooltip_button"))
+	tooltip_button_hover = AssembleTooltipTexture(textures.Texture("tooltip_button_hover"))
+	tooltip_input = AssembleTooltipTexture(textures.Texture("tooltip_input"))
+	tooltip_input_focused = AssembleTooltipTexture(textures.Texture("tooltip_input_focused"))
+	tooltip_neutral = AssembleTooltipTexture(textures.Texture("tooltip_neutral"))
 }
 
 // Which side of the cursor to prefer for displaying the tooltip.
@@ -0,0 +1,104 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// AtomicWriter replaces a group of files on the disk,
+// so that a crash in the middle of saving never leaves any of them truncated.
+//
+// Each added file is first written to a temporary file next to it, and synced to the disk.
+// Nothing is replaced until Commit() is called, which renames temporary files into place
+// in the same order they were added. So, files that are referenced by other files should be added first.
+type AtomicWriter struct {
+	pending []pendingFile
+}
+
+type pendingFile struct {
+	tmpPath, path string
+}
+
+func NewAtomicWriter() *AtomicWriter {
+	return &AtomicWriter{}
+}
+
+// Add writes data to a temporary file, that will replace the file at path on Commit()
+func (w *AtomicWriter) Add(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	w.pending = append(w.pending, pendingFile{tmpPath: tmpPath, path: path})
+	return nil
+}
+
+// Commit moves all added files into place
+func (w *AtomicWriter) Commit() error {
+	dirs := make(map[string]bool)
+	for i, file := range w.pending {
+		if err := os.Rename(file.tmpPath, file.path); err != nil {
+			// leave the rest of the files untouched
+			w.pending = w.pending[i:]
+			w.Discard()
+			return err
+		}
+		dirs[filepath.Dir(file.path)] = true
+	}
+	w.pending = nil
+
+	// make sure that renames themselves reach the disk
+	for dir := range dirs {
+		syncDir(dir)
+	}
+	return nil
+}
+
+// Discard removes temporary files, that weren't committed yet
+func (w *AtomicWriter) Discard() {
+	for _, file := range w.pending {
+		os.Remove(file.tmpPath)
+	}
+	w.pending = nil
+}
+
+// WriteFileAtomic atomically replaces a single file
+func WriteFileAtomic(path string, data []byte) error {
+	w := NewAtomicWriter()
+	if err := w.Add(path, data); err != nil {
+		return err
+	}
+	return w.Commit()
+}
+
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	// Not all platforms support syncing directories (e.g. Windows), so the error is ignored
+	d.Sync()
+}
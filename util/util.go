@@ -17,6 +17,13 @@ func Clamp[T constraints.Integer | constraints.Float](val, min, max T) T {
 	}
 }
 
+func Max[T constraints.Integer | constraints.Float](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 func RandomChoice[T any](objects []T) T {
 	if len(objects) == 0 {
 		log.Panicln("array with zero length")
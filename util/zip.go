@@ -0,0 +1,130 @@
+package util
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Leftover temporary files are not archived
+func skipZipEntry(path string, d fs.DirEntry) bool {
+	return d.IsDir() || strings.HasSuffix(path, ".tmp")
+}
+
+// ZipDirectorySize returns total size of the files, that ZipDirectory would archive
+func ZipDirectorySize(dir string) (size int64, err error) {
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if skipZipEntry(path, d) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// ZipDirectory writes contents of the directory to the zip archive, under the given prefix.
+// Leftover temporary files (*.tmp) are skipped.
+// If progress isn't nil, it is called with the amount of bytes written, after each file.
+func ZipDirectory(archive *zip.Writer, dir, prefix string, progress func(written int64)) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if skipZipEntry(path, d) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		// zip archives always use forward slashes
+		w, err := archive.Create(filepath.ToSlash(filepath.Join(prefix, rel)))
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		written, err := io.Copy(w, f)
+		if err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(written)
+		}
+		return nil
+	})
+}
+
+// UnzipDirectory extracts files from the archive, that are located under the given prefix, to the directory
+func UnzipDirectory(archive *zip.Reader, dir, prefix string) error {
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		rel := file.Name
+		if prefix != "" {
+			if !strings.HasPrefix(rel, prefix+"/") {
+				continue
+			}
+			rel = strings.TrimPrefix(rel, prefix+"/")
+		}
+
+		// don't let malicious archives write outside of the directory
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid file path in the archive: %v", file.Name)
+		}
+
+		if err := unzipFile(file, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unzipFile(file *zip.File, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	r, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
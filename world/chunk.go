@@ -2,10 +2,11 @@ package world
 
 import (
 	"log"
+	"math/rand"
 
-	"github.com/3elDU/bamboo/scene_manager"
+	"github.com/3elDU/bamboo/clock"
+	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/types"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type Chunk struct {
@@ -13,12 +14,10 @@ type Chunk struct {
 	x, y   uint64
 	blocks [16][16]types.Block
 
-	texture *ebiten.Image
-
 	// Whether a chunk has been modified since last update
 	modified bool
 	// similar to modified, but indicates that redraw is required
-	// resets on Chunk.Render()
+	// resets on Chunk.Redrawn()
 	needsRedraw     bool
 	recursiveRedraw bool
 
@@ -26,16 +25,28 @@ type Chunk struct {
 
 	// Prevents the chunks from being saved to the disk
 	preventSaving bool
+
+	// Blocks, that are updated every tick. See types.UpdatableBlock
+	updatable []types.UpdatableBlock
+	// Ticks, that blocks asked for with ScheduleTick()
+	scheduled []scheduledTick
+}
+
+type scheduledTick struct {
+	x, y uint
+	// The tick is dropped, if the block at x, y was replaced with another one
+	block types.Block
+	// Value of clock.Ticks(), when the block should be ticked
+	at uint64
 }
 
 // NewChunk creates new empty Chunk at specified chunk coordinates
 func NewChunk(cx, cy uint64) *Chunk {
 	return &Chunk{
 		x: cx, y: cy,
-		texture:      ebiten.NewImage(256, 256),
 		modified:     true,
 		needsRedraw:  true,
-		lastAccessed: scene_manager.Ticks(),
+		lastAccessed: clock.Ticks(),
 	}
 }
 
@@ -45,11 +56,61 @@ func (c *Chunk) PreventSaving() {
 	c.preventSaving = true
 }
 
+// Only blocks that opt in are ticked, see types.UpdatableBlock,
+// types.ScheduledTickBlock and types.RandomTickBlock
 func (c *Chunk) Update(world types.World) {
-	for x := 0; x < 16; x++ {
-		for y := 0; y < 16; y++ {
-			c.blocks[x][y].Update(world)
+	for _, block := range c.updatable {
+		block.Update(world)
+	}
+
+	c.runScheduledTicks(world)
+
+	for i := 0; i < config.RandomTicksPerChunk; i++ {
+		n := rand.Intn(16 * 16)
+		if block, ok := c.blocks[n/16][n%16].(types.RandomTickBlock); ok {
+			block.RandomTick(world)
+		}
+	}
+}
+
+func (c *Chunk) ScheduleTick(x, y uint, delay uint64) {
+	if x > 15 || y > 15 {
+		log.Panicf("invalid coordinates: %v, %v", x, y)
+	}
+	// the tick would be run in the same update otherwise, possibly forever
+	if delay == 0 {
+		delay = 1
+	}
+	c.scheduled = append(c.scheduled, scheduledTick{
+		x: x, y: y,
+		block: c.blocks[x][y],
+		at:    clock.Ticks() + delay,
+	})
+}
+
+func (c *Chunk) runScheduledTicks(world types.World) {
+	if len(c.scheduled) == 0 {
+		return
+	}
+
+	now := clock.Ticks()
+	var due []scheduledTick
+	pending := c.scheduled[:0]
+	for _, tick := range c.scheduled {
+		if tick.at <= now {
+			due = append(due, tick)
+		} else {
+			pending = append(pending, tick)
+		}
+	}
+	// blocks may schedule new ticks from ScheduledTick()
+	c.scheduled = pending
+
+	for _, tick := range due {
+		if c.blocks[tick.x][tick.y] != tick.block {
+			continue
 		}
+		tick.block.(types.ScheduledTickBlock).ScheduledTick(world)
 	}
 }
 
@@ -65,7 +126,7 @@ func (c *Chunk) At(x, y uint) types.Block {
 	if x > 16 || y > 16 {
 		log.Panicf("invalid coordinates: %v, %v", x, y)
 	}
-	c.lastAccessed = scene_manager.Ticks()
+	c.lastAccessed = clock.Ticks()
 	return c.blocks[x][y]
 }
 
@@ -74,15 +135,39 @@ func (c *Chunk) SetBlock(x, y uint, block types.Block) {
 		log.Panicf("invalid coordinates: %v, %v", x, y)
 	}
 
+	if old, ok := c.blocks[x][y].(types.UpdatableBlock); ok {
+		c.removeUpdatable(old)
+	}
+
 	block.SetParentChunk(c)
 	block.SetCoords(types.Vec2u{X: c.x*16 + uint64(x), Y: c.y*16 + uint64(y)})
 	c.blocks[x][y] = block
-	c.lastAccessed = scene_manager.Ticks()
+
+	if updatable, ok := block.(types.UpdatableBlock); ok {
+		c.updatable = append(c.updatable, updatable)
+	}
+	if scheduled, ok := block.(types.ScheduledTickBlock); ok {
+		if delay := scheduled.InitialTickDelay(); delay > 0 {
+			c.ScheduleTick(x, y, delay)
+		}
+	}
+	c.lastAccessed = clock.Ticks()
 	c.modified = true
 	c.needsRedraw = true
 	c.recursiveRedraw = true
 }
 
+// The list is copied, because the block may be replaced while Update() iterates over it
+func (c *Chunk) removeUpdatable(block types.UpdatableBlock) {
+	updatable := make([]types.UpdatableBlock, 0, len(c.updatable))
+	for _, other := range c.updatable {
+		if other != block {
+			updatable = append(updatable, other)
+		}
+	}
+	c.updatable = updatable
+}
+
 func (c *Chunk) TriggerRedraw(recursive bool) {
 	c.needsRedraw = true
 	c.recursiveRedraw = recursive
@@ -94,6 +179,14 @@ func (c *Chunk) MarkAsModified() {
 	c.TriggerRedraw(true)
 }
 
-func (c *Chunk) Texture() *ebiten.Image {
-	return c.texture
+// Returns true, if the chunk has to be redrawn.
+// recursive means that blocks may trigger a redraw of the neighboring chunks
+func (c *Chunk) NeedsRedraw() (needed bool, recursive bool) {
+	return c.needsRedraw, c.recursiveRedraw
+}
+
+// Called by the renderer, after the chunk was redrawn
+func (c *Chunk) Redrawn() {
+	c.needsRedraw = false
+	c.recursiveRedraw = false
 }
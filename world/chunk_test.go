@@ -0,0 +1,64 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/3elDU/bamboo/types"
+)
+
+// Before the tick scheduler, every block had Update(), and it was called on all 256 blocks of the chunk every tick.
+// Most blocks did nothing in it, so blocks, that don't need updates now, get an empty one.
+type legacyBlock struct {
+	types.Block
+}
+
+func (legacyBlock) Update(types.World) {}
+
+// Blocks of the chunk, as the chunk would see them before the tick scheduler
+func legacyBlocks(c *Chunk) (blocks [16][16]types.UpdatableBlock) {
+	for x := range c.blocks {
+		for y := range c.blocks[x] {
+			if block, ok := c.blocks[x][y].(types.UpdatableBlock); ok {
+				blocks[x][y] = block
+			} else {
+				blocks[x][y] = legacyBlock{c.blocks[x][y]}
+			}
+		}
+	}
+	return
+}
+
+// How chunks were updated before the tick scheduler: every block is updated every tick
+func updateEveryBlock(blocks *[16][16]types.UpdatableBlock, world types.World) {
+	for x := range blocks {
+		for y := range blocks[x] {
+			blocks[x][y].Update(world)
+		}
+	}
+}
+
+// Compares the time of one world tick with all the chunks loaded
+func BenchmarkChunkUpdate(b *testing.B) {
+	w, chunks := generateBenchmarkWorld()
+
+	b.Run("every-block", func(b *testing.B) {
+		blocks := make([][16][16]types.UpdatableBlock, len(chunks))
+		for i, c := range chunks {
+			blocks[i] = legacyBlocks(c)
+		}
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			for j := range blocks {
+				updateEveryBlock(&blocks[j], w)
+			}
+		}
+	})
+	b.Run("scheduler", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, c := range chunks {
+				c.Update(w)
+			}
+		}
+	})
+}
@@ -0,0 +1,337 @@
+// Compression and palette encoding of chunk records
+
+package world
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+
+	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/types"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec compresses chunk records before they are written to the region file
+type Codec interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// Codec ID is stored in each chunk record, so changing the codec of a world
+// doesn't break chunks that were saved with the previous one
+type registeredCodec struct {
+	id    byte
+	name  string
+	codec Codec
+}
+
+var (
+	codecsByID   = make(map[byte]registeredCodec)
+	codecsByName = make(map[string]registeredCodec)
+)
+
+// RegisterCodec makes a codec available for saving and loading chunks.
+// IDs are written to the disk, so they must never be reused for a different codec.
+func RegisterCodec(id byte, name string, codec Codec) {
+	if _, exists := codecsByID[id]; exists {
+		log.Panicf("RegisterCodec() - codec id %v is already taken", id)
+	}
+	if _, exists := codecsByName[name]; exists {
+		log.Panicf("RegisterCodec() - codec %v is already registered", name)
+	}
+
+	c := registeredCodec{id: id, name: name, codec: codec}
+	codecsByID[id] = c
+	codecsByName[name] = c
+}
+
+func init() {
+	RegisterCodec(0, "none", noneCodec{})
+	RegisterCodec(1, "gzip", gzipCodec{})
+	RegisterCodec(2, "zstd", newZstdCodec())
+}
+
+// Names of all registered codecs, sorted by their IDs
+func CodecNames() []string {
+	names := make([]string, 0, len(codecsByID))
+	for id := 0; id < 256; id++ {
+		if c, exists := codecsByID[byte(id)]; exists {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+// Codec, that new chunks of the world are saved with
+func worldCodec(metadata types.Save) string {
+	if metadata.Codec == "" {
+		return config.ChunkCodec
+	}
+	return metadata.Codec
+}
+
+func CodecExists(name string) bool {
+	_, exists := codecsByName[name]
+	return exists
+}
+
+// Stores the data as is
+type noneCodec struct{}
+
+func (noneCodec) Compress(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+func (noneCodec) Decompress(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) Compress(data []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCodec) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// Compresses better than gzip, and decompresses a lot faster
+type zstdCodec struct {
+	// both are safe for concurrent use with EncodeAll() and DecodeAll()
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+func newZstdCodec() zstdCodec {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		log.Panicf("failed to create zstd encoder - %v", err)
+	}
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		log.Panicf("failed to create zstd decoder - %v", err)
+	}
+	return zstdCodec{encoder: encoder, decoder: decoder}
+}
+
+func (c zstdCodec) Compress(data []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(data, nil), nil
+}
+
+func (c zstdCodec) Decompress(data []byte) ([]byte, error) {
+	return c.decoder.DecodeAll(data, nil)
+}
+
+// Chunk as it is actually stored on the disk.
+//
+// Most of the chunk is usually made of a few kinds of blocks with identical state,
+// so each distinct block is stored only once in the palette,
+// and tiles reference it by index.
+type paletteChunk struct {
+	Version int
+
+	X, Y    uint64
+	Palette []SavedBlock
+	// Key of each palette entry's block type.
+	// Numeric IDs in the palette are only valid for the version of the game that saved the chunk.
+	Keys []string
+	// Index into the palette for each block, column by column
+	Tiles [16 * 16]uint8
+}
+
+// Chunk records, that were saved before compression was introduced, are plain gob streams.
+// Gob streams never start with a zero byte, so it is used to tell them apart.
+const chunkRecordMarker = 0
+
+func paletteEncode(chunk SavedChunk) paletteChunk {
+	encoded := paletteChunk{
+		Version: chunk.Version,
+		X:       chunk.X, Y: chunk.Y,
+	}
+
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 16; y++ {
+			block := chunk.Data[x][y]
+
+			index := -1
+			for i, entry := range encoded.Palette {
+				if entry.Type == block.Type && reflect.DeepEqual(entry.State, block.State) {
+					index = i
+					break
+				}
+			}
+			if index == -1 {
+				index = len(encoded.Palette)
+				encoded.Palette = append(encoded.Palette, block)
+				encoded.Keys = append(encoded.Keys, block.Type.Key())
+			}
+
+			// there are only 256 blocks in the chunk, so the index always fits
+			encoded.Tiles[x*16+y] = uint8(index)
+		}
+	}
+
+	return encoded
+}
+
+func paletteDecode(encoded paletteChunk) (*SavedChunk, error) {
+	chunk := &SavedChunk{
+		Version: encoded.Version,
+		X:       encoded.X, Y: encoded.Y,
+	}
+
+	if len(encoded.Keys) == 0 {
+		// saved before block keys were introduced
+		if err := resolveLegacyBlockTypes(encoded.Palette); err != nil {
+			return nil, err
+		}
+	} else {
+		if len(encoded.Keys) != len(encoded.Palette) {
+			return nil, fmt.Errorf("palette has %v entries, but %v keys", len(encoded.Palette), len(encoded.Keys))
+		}
+		for i, key := range encoded.Keys {
+			blockType, exists := types.BlockTypeByKey(key)
+			if !exists {
+				return nil, fmt.Errorf("unknown block %q", key)
+			}
+			encoded.Palette[i].Type = blockType
+		}
+	}
+
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 16; y++ {
+			index := int(encoded.Tiles[x*16+y])
+			if index >= len(encoded.Palette) {
+				return nil, fmt.Errorf("palette index %v is out of range", index)
+			}
+			chunk.Data[x][y] = encoded.Palette[index]
+		}
+	}
+
+	return chunk, nil
+}
+
+// Encodes the chunk into a record, using the codec with given name
+func encodeChunk(chunk SavedChunk, codecName string) ([]byte, error) {
+	c, ok := codecsByName[codecName]
+	if !ok {
+		return nil, fmt.Errorf("unknown chunk codec %v", codecName)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(paletteEncode(chunk)); err != nil {
+		return nil, err
+	}
+
+	compressed, err := c.codec.Compress(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{chunkRecordMarker, c.id}, compressed...), nil
+}
+
+// Decodes the chunk record, saved with any of the registered codecs, or by older versions of the game
+func decodeChunk(record []byte) (*SavedChunk, error) {
+	if len(record) == 0 || record[0] != chunkRecordMarker {
+		// uncompressed chunk from older version
+		chunk := new(SavedChunk)
+		if err := gob.NewDecoder(bytes.NewReader(record)).Decode(chunk); err != nil {
+			return nil, err
+		}
+		for x := range chunk.Data {
+			if err := resolveLegacyBlockTypes(chunk.Data[x][:]); err != nil {
+				return nil, err
+			}
+		}
+		return chunk, nil
+	}
+
+	if len(record) < 2 {
+		return nil, errors.New("chunk record is missing codec id")
+	}
+	c, ok := codecsByID[record[1]]
+	if !ok {
+		return nil, fmt.Errorf("unknown chunk codec id %v", record[1])
+	}
+
+	data, err := c.codec.Decompress(record[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	var encoded paletteChunk
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&encoded); err != nil {
+		return nil, err
+	}
+	return paletteDecode(encoded)
+}
+
+// Keys of the block types, in the order of the numeric IDs they had before keys were saved.
+// Chunks from older versions only have the IDs, so this list must never be changed.
+var legacyBlockKeys = [...]string{
+	"bamboo:empty",
+	"bamboo:stone",
+	"bamboo:water",
+	"bamboo:sand",
+	"bamboo:grass",
+	"bamboo:snow",
+	"bamboo:short_grass",
+	"bamboo:tall_grass",
+	"bamboo:flowers",
+	"bamboo:pine_tree",
+	"bamboo:red_mushroom",
+	"bamboo:white_mushroom",
+	"bamboo:cave_entrance",
+	"bamboo:cave_wall",
+	"bamboo:cave_floor",
+	"bamboo:cave_exit",
+	"bamboo:pine_sapling",
+	"bamboo:campfire",
+	"bamboo:berry_bush",
+	"bamboo:sand_with_stones",
+	"bamboo:sand_with_clay",
+	"bamboo:pit",
+	"bamboo:iron_ore",
+	"bamboo:furnace",
+	"bamboo:cave_descent",
+	"bamboo:copper_ore",
+	"bamboo:gold_ore",
+	"bamboo:coal_ore",
+}
+
+// Translates numeric IDs, saved by older versions of the game, to current ones
+func resolveLegacyBlockTypes(blocks []SavedBlock) error {
+	for i := range blocks {
+		id := blocks[i].Type
+		if id < 0 || int(id) >= len(legacyBlockKeys) {
+			return fmt.Errorf("unknown legacy block id %v", int(id))
+		}
+		blockType, exists := types.BlockTypeByKey(legacyBlockKeys[id])
+		if !exists {
+			return fmt.Errorf("unknown block %q", legacyBlockKeys[id])
+		}
+		blocks[i].Type = blockType
+	}
+	return nil
+}
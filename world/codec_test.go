@@ -0,0 +1,118 @@
+package world
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	_ "github.com/3elDU/bamboo/blocks_impl"
+	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/world_type"
+	"github.com/3elDU/bamboo/worldgen"
+)
+
+// Generates a square of overworld chunks around the center of the world.
+// Returned world only holds those chunks, it doesn't load, save or generate anything in the background.
+func generateBenchmarkWorld() (*World, []*Chunk) {
+	metadata := types.Save{
+		Seed:      1,
+		WorldType: world_type.Overworld,
+		Size:      SizeForWorldType(world_type.Overworld),
+	}
+	generator := worldgen.NewWorldgenForWorld(metadata)
+
+	w := &World{
+		generator: generator,
+		metadata:  metadata,
+		chunks:    make(map[types.Vec2u]*Chunk),
+	}
+
+	center := metadata.Size.X / 16 / 2
+	chunks := make([]*Chunk, 0)
+	for cx := center - 4; cx < center+4; cx++ {
+		for cy := center - 4; cy < center+4; cy++ {
+			c := NewChunk(cx, cy)
+			generator.GenerateImmediately(c)
+			chunks = append(chunks, c)
+			w.chunks[c.Coords()] = c
+		}
+	}
+	return w, chunks
+}
+
+func generateBenchmarkChunks() []SavedChunk {
+	chunks := make([]SavedChunk, 0)
+	_, generated := generateBenchmarkWorld()
+	for _, c := range generated {
+		chunks = append(chunks, c.toSaved())
+	}
+	return chunks
+}
+
+// Encodes the chunk as plain gob, like it was saved before palettes and compression
+func encodeLegacyChunk(chunk SavedChunk) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := gob.NewEncoder(buf).Encode(chunk)
+	return buf.Bytes(), err
+}
+
+var benchmarkEncoders = []struct {
+	name   string
+	encode func(SavedChunk) ([]byte, error)
+}{
+	{"legacy", encodeLegacyChunk},
+	{"none", func(chunk SavedChunk) ([]byte, error) { return encodeChunk(chunk, "none") }},
+	{"gzip", func(chunk SavedChunk) ([]byte, error) { return encodeChunk(chunk, "gzip") }},
+	{"zstd", func(chunk SavedChunk) ([]byte, error) { return encodeChunk(chunk, "zstd") }},
+}
+
+// Reports size of the chunk record on the disk for each encoding
+func BenchmarkChunkEncode(b *testing.B) {
+	chunks := generateBenchmarkChunks()
+
+	for _, encoder := range benchmarkEncoders {
+		b.Run(encoder.name, func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				size = 0
+				for _, chunk := range chunks {
+					record, err := encoder.encode(chunk)
+					if err != nil {
+						b.Fatal(err)
+					}
+					size += len(record)
+				}
+			}
+			b.ReportMetric(float64(size)/float64(len(chunks)), "bytes/chunk")
+		})
+	}
+}
+
+func BenchmarkChunkDecode(b *testing.B) {
+	chunks := generateBenchmarkChunks()
+
+	for _, encoder := range benchmarkEncoders {
+		records := make([][]byte, len(chunks))
+		for i, chunk := range chunks {
+			record, err := encoder.encode(chunk)
+			if err != nil {
+				b.Fatal(err)
+			}
+			records[i] = record
+		}
+
+		b.Run(encoder.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, record := range records {
+					saved, err := decodeChunk(record)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if _, err := loadSavedChunk(saved); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
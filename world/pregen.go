@@ -0,0 +1,126 @@
+// Pre-generation of chunks, so the player doesn't have to wait for them while exploring
+
+package world
+
+import (
+	"log"
+	"sort"
+
+	"github.com/3elDU/bamboo/types"
+)
+
+// Pregenerator generates and saves every chunk in the area, that isn't saved yet.
+//
+// Already saved chunks are skipped, so if pre-generation was interrupted,
+// running it again continues where it stopped.
+type Pregenerator struct {
+	metadata  types.Save
+	generator types.WorldGenerator
+
+	// chunks that are left to process, closest to the center go first
+	queue []types.Vec2u
+	total int
+
+	// Chunks that were generated, and chunks that were already saved
+	Generated, Skipped int
+
+	// Returns true for chunks that must be left alone, e.g. because they are loaded in the world
+	skip func(cx, cy uint64) bool
+}
+
+// NewPregenerator prepares pre-generation of a rectangle of chunks.
+// The rectangle is clipped to the world borders.
+func NewPregenerator(metadata types.Save, generator types.WorldGenerator, cx, cy, width, height uint64) *Pregenerator {
+	sizeX, sizeY := metadata.Size.X/16, metadata.Size.Y/16
+	queue := make([]types.Vec2u, 0)
+	for x := cx; x < cx+width && x < sizeX; x++ {
+		for y := cy; y < cy+height && y < sizeY; y++ {
+			queue = append(queue, types.Vec2u{X: x, Y: y})
+		}
+	}
+
+	center := types.Vec2u{X: cx + width/2, Y: cy + height/2}
+	return newPregenerator(metadata, generator, queue, center)
+}
+
+// NewSpawnPregenerator prepares pre-generation of all chunks within the radius (in chunks) around the spawn point
+func NewSpawnPregenerator(metadata types.Save, generator types.WorldGenerator, radius uint64) *Pregenerator {
+	spawn := types.Vec2u{X: metadata.SpawnPoint.X / 16, Y: metadata.SpawnPoint.Y / 16}
+	sizeX, sizeY := metadata.Size.X/16, metadata.Size.Y/16
+
+	queue := make([]types.Vec2u, 0)
+	for x := int64(spawn.X) - int64(radius); x <= int64(spawn.X)+int64(radius); x++ {
+		for y := int64(spawn.Y) - int64(radius); y <= int64(spawn.Y)+int64(radius); y++ {
+			if x < 0 || y < 0 || uint64(x) >= sizeX || uint64(y) >= sizeY {
+				continue
+			}
+			dx, dy := x-int64(spawn.X), y-int64(spawn.Y)
+			if dx*dx+dy*dy > int64(radius*radius) {
+				continue
+			}
+			queue = append(queue, types.Vec2u{X: uint64(x), Y: uint64(y)})
+		}
+	}
+
+	return newPregenerator(metadata, generator, queue, spawn)
+}
+
+func newPregenerator(metadata types.Save, generator types.WorldGenerator, queue []types.Vec2u, center types.Vec2u) *Pregenerator {
+	distance := func(c types.Vec2u) int64 {
+		dx, dy := int64(c.X)-int64(center.X), int64(c.Y)-int64(center.Y)
+		return dx*dx + dy*dy
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		return distance(queue[i]) < distance(queue[j])
+	})
+
+	return &Pregenerator{
+		metadata:  metadata,
+		generator: generator,
+		queue:     queue,
+		total:     len(queue),
+		skip:      func(cx, cy uint64) bool { return false },
+	}
+}
+
+// Pregenerate prepares pre-generation for the world, that is currently being played.
+// Chunks that are loaded in the world are left to the world itself.
+// Must be used from the main thread, like the world.
+func (world *World) Pregenerate(radius uint64) *Pregenerator {
+	p := NewSpawnPregenerator(world.metadata, world.generator, radius)
+	p.skip = world.ChunkExists
+	return p
+}
+
+// Step processes the next chunk in the queue. Returns false, when there is nothing left to do.
+func (p *Pregenerator) Step() bool {
+	if len(p.queue) == 0 {
+		return false
+	}
+
+	coords := p.queue[0]
+	p.queue = p.queue[1:]
+
+	if p.skip(coords.X, coords.Y) || ChunkExistsOnDisk(p.metadata, coords.X, coords.Y) {
+		p.Skipped++
+	} else {
+		c := NewChunk(coords.X, coords.Y)
+		p.generator.GenerateImmediately(c)
+		c.Save(p.metadata)
+		p.Generated++
+	}
+
+	if len(p.queue) == 0 {
+		log.Printf("Pregenerator - done; generated %v chunks, %v were already there", p.Generated, p.Skipped)
+	}
+	return true
+}
+
+// Progress returns the number of processed chunks, and the total number of chunks in the area
+func (p *Pregenerator) Progress() (done, total int) {
+	return p.total - len(p.queue), p.total
+}
+
+func (p *Pregenerator) Done() bool {
+	return len(p.queue) == 0
+}
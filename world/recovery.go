@@ -0,0 +1,114 @@
+// Everything related to recovering from corrupted saves
+
+package world
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/types"
+	"github.com/google/uuid"
+)
+
+// ErrNewerSaveVersion is returned, when the save was made by a newer version of the game.
+// Such saves must not be repaired or overwritten.
+var ErrNewerSaveVersion = errors.New("saved with newer save format version")
+
+// Marks, when the player last saw that the world was repaired.
+// Lives in the quarantine directory, but isn't quarantined data itself.
+const repairsSeenFile = ".seen"
+
+// CorruptChunkError is returned when a saved chunk fails the checksum, or can't be decoded
+type CorruptChunkError struct {
+	X, Y uint64
+	// Raw chunk record, as it was read from the disk. May be nil
+	Data []byte
+	Err  error
+}
+
+func (e *CorruptChunkError) Error() string {
+	return fmt.Sprintf("chunk %v, %v is corrupt: %v", e.X, e.Y, e.Err)
+}
+
+func (e *CorruptChunkError) Unwrap() error {
+	return e.Err
+}
+
+func quarantineDirectory(baseUUID uuid.UUID) string {
+	return filepath.Join(config.WorldSaveDirectory, baseUUID.String(), config.QuarantineDirectory)
+}
+
+// QuarantineFile puts a copy of broken save data in the corrupt/ folder of the save,
+// so it is not lost, and can be inspected later
+func QuarantineFile(baseUUID uuid.UUID, name string, data []byte) {
+	dir := quarantineDirectory(baseUUID)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		log.Printf("QuarantineFile() - failed to create quarantine directory: %v", err)
+		return
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%v_%v", time.Now().Format("2006-01-02_15-04-05"), name))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		log.Printf("QuarantineFile() - failed to write %v: %v", path, err)
+		return
+	}
+	log.Printf("QuarantineFile() - quarantined %v", path)
+}
+
+// Moves corrupted chunk record to the quarantine, and removes it from the region,
+// so it will be generated from scratch
+func quarantineChunk(metadata types.Save, corrupt *CorruptChunkError) {
+	QuarantineFile(metadata.BaseUUID, fmt.Sprintf("%v_chunk_%v_%v.bin", metadata.UUID, corrupt.X, corrupt.Y), corrupt.Data)
+
+	if err := deleteChunkRecord(metadata, corrupt.X, corrupt.Y); err != nil {
+		log.Printf("quarantineChunk() - failed to remove chunk from the region: %v", err)
+	}
+}
+
+// WasRepaired returns true if some of the save data was found corrupted, and had to be repaired,
+// since the last call to MarkRepairsSeen()
+func WasRepaired(baseUUID uuid.UUID) bool {
+	dir := quarantineDirectory(baseUUID)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	var seen time.Time
+	if info, err := os.Stat(filepath.Join(dir, repairsSeenFile)); err == nil {
+		seen = info.ModTime()
+	}
+	for _, entry := range entries {
+		if entry.Name() == repairsSeenFile {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().After(seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkRepairsSeen is called, when the player opens the world,
+// so it is shown as repaired only if something else gets quarantined
+func MarkRepairsSeen(baseUUID uuid.UUID) {
+	dir := quarantineDirectory(baseUUID)
+	if _, err := os.Stat(dir); err != nil {
+		// nothing was ever quarantined
+		return
+	}
+
+	path := filepath.Join(dir, repairsSeenFile)
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		log.Printf("MarkRepairsSeen() - failed to write %v: %v", path, err)
+		return
+	}
+	// writing an empty file over an empty one doesn't always update the modification time
+	now := time.Now()
+	os.Chtimes(path, now, now)
+}
@@ -0,0 +1,329 @@
+// Region files pack multiple chunks into a single file on the disk
+
+package world
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/types"
+)
+
+// Region file layout:
+//
+//	header: config.RegionSize*config.RegionSize entries, one for each chunk in the region.
+//	        Each entry is two big-endian uint32 values: offset of the chunk record in the file, and its length.
+//	        An entry with zero length means that the chunk wasn't saved yet.
+//	records: encoded chunks, referenced by the header.
+//	         Each record starts with big-endian CRC-32 checksum of the rest of the record.
+//
+// When the chunk is saved again, new record is appended to the end of the file,
+// and the header entry is updated to point to it. When the space taken by stale records
+// gets larger than the space taken by actual ones, the region is compacted.
+const (
+	regionEntries    = config.RegionSize * config.RegionSize
+	regionHeaderSize = regionEntries * 8
+)
+
+// Region files are accessed both from the main thread and from the SaverLoader goroutines
+var regionMutex sync.Mutex
+
+type regionEntry struct {
+	Offset, Length uint32
+}
+
+func regionPath(metadata types.Save, cx, cy uint64) string {
+	return filepath.Join(saveDirectory(metadata),
+		fmt.Sprintf("region_%v_%v.bin", cx/config.RegionSize, cy/config.RegionSize))
+}
+
+// Returns index of the chunk in the region header
+func regionIndex(cx, cy uint64) int {
+	return int(cy%config.RegionSize)*config.RegionSize + int(cx%config.RegionSize)
+}
+
+func readRegionHeader(f *os.File) ([]regionEntry, error) {
+	header := make([]regionEntry, regionEntries)
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(f, binary.BigEndian, header); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+func writeRegionEntry(f *os.File, index int, entry regionEntry) error {
+	if _, err := f.Seek(int64(index)*8, io.SeekStart); err != nil {
+		return err
+	}
+	return binary.Write(f, binary.BigEndian, entry)
+}
+
+func chunkRecordExists(metadata types.Save, cx, cy uint64) bool {
+	regionMutex.Lock()
+	defer regionMutex.Unlock()
+
+	f, err := os.Open(regionPath(metadata, cx, cy))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	var entry regionEntry
+	if _, err := f.Seek(int64(regionIndex(cx, cy))*8, io.SeekStart); err != nil {
+		return false
+	}
+	if err := binary.Read(f, binary.BigEndian, &entry); err != nil {
+		return false
+	}
+	return entry.Length != 0
+}
+
+// Returns contents of the chunk record from the region file, after verifying the checksum.
+// If the chunk isn't saved, returns nil without an error.
+// If the record is damaged, returns *CorruptChunkError.
+func readChunkRecord(metadata types.Save, cx, cy uint64) ([]byte, error) {
+	regionMutex.Lock()
+	defer regionMutex.Unlock()
+
+	f, err := os.Open(regionPath(metadata, cx, cy))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	header, err := readRegionHeader(f)
+	if err != nil {
+		return nil, &CorruptChunkError{X: cx, Y: cy, Err: fmt.Errorf("failed to read region header: %v", err)}
+	}
+
+	entry := header[regionIndex(cx, cy)]
+	if entry.Length == 0 {
+		return nil, nil
+	}
+
+	record := make([]byte, entry.Length)
+	n, err := f.ReadAt(record, int64(entry.Offset))
+	if err != nil {
+		return nil, &CorruptChunkError{X: cx, Y: cy, Data: record[:n], Err: fmt.Errorf("failed to read chunk record: %v", err)}
+	}
+	if len(record) < 4 {
+		return nil, &CorruptChunkError{X: cx, Y: cy, Data: record, Err: errors.New("chunk record is too short")}
+	}
+	if binary.BigEndian.Uint32(record) != crc32.ChecksumIEEE(record[4:]) {
+		return nil, &CorruptChunkError{X: cx, Y: cy, Data: record, Err: errors.New("checksum mismatch")}
+	}
+	return record[4:], nil
+}
+
+// Writes the chunk record to the region file, creating the region if it doesn't exist yet
+func writeChunkRecord(metadata types.Save, cx, cy uint64, data []byte) error {
+	regionMutex.Lock()
+	defer regionMutex.Unlock()
+
+	path := regionPath(metadata, cx, cy)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	// Newly created region, write an empty header first
+	if info.Size() < regionHeaderSize {
+		if err := f.Truncate(regionHeaderSize); err != nil {
+			return err
+		}
+	}
+
+	record := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(record, crc32.ChecksumIEEE(data))
+	copy(record[4:], data)
+
+	end, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(record); err != nil {
+		return err
+	}
+	if err := writeRegionEntry(f, regionIndex(cx, cy), regionEntry{
+		Offset: uint32(end),
+		Length: uint32(len(record)),
+	}); err != nil {
+		return err
+	}
+
+	header, err := readRegionHeader(f)
+	if err != nil {
+		return err
+	}
+	var used int64
+	for _, entry := range header {
+		used += int64(entry.Length)
+	}
+	if wasted := end + int64(len(record)) - regionHeaderSize - used; wasted <= used {
+		return nil
+	}
+
+	// The region has to be closed before it can be replaced with the compacted one
+	f.Close()
+	return compactRegion(path)
+}
+
+// Removes the chunk from the region, so it is treated as never saved
+func deleteChunkRecord(metadata types.Save, cx, cy uint64) error {
+	regionMutex.Lock()
+	defer regionMutex.Unlock()
+
+	f, err := os.OpenFile(regionPath(metadata, cx, cy), os.O_RDWR, 0644)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return writeRegionEntry(f, regionIndex(cx, cy), regionEntry{})
+}
+
+// Rewrites the region file, leaving out stale records
+func compactRegion(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	header, err := readRegionHeader(f)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer tmp.Close()
+
+	compacted := make([]regionEntry, regionEntries)
+	offset := uint32(regionHeaderSize)
+	if err := tmp.Truncate(regionHeaderSize); err != nil {
+		return err
+	}
+	for i, entry := range header {
+		if entry.Length == 0 {
+			continue
+		}
+
+		data := make([]byte, entry.Length)
+		if _, err := f.ReadAt(data, int64(entry.Offset)); err != nil {
+			return err
+		}
+		if _, err := tmp.WriteAt(data, int64(offset)); err != nil {
+			return err
+		}
+
+		compacted[i] = regionEntry{Offset: offset, Length: entry.Length}
+		offset += entry.Length
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := binary.Write(tmp, binary.BigEndian, compacted); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	f.Close()
+
+	return os.Rename(tmpPath, path)
+}
+
+// SavedChunks returns coordinates of all chunks of the world, that are stored in region files
+func SavedChunks(metadata types.Save) ([]types.Vec2u, error) {
+	regionMutex.Lock()
+	defer regionMutex.Unlock()
+
+	regions, err := filepath.Glob(filepath.Join(saveDirectory(metadata), "region_*_*.bin"))
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([]types.Vec2u, 0)
+	for _, path := range regions {
+		var rx, ry uint64
+		if _, err := fmt.Sscanf(filepath.Base(path), "region_%d_%d.bin", &rx, &ry); err != nil {
+			continue
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		header, err := readRegionHeader(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read header of %v: %v", path, err)
+		}
+
+		for i, entry := range header {
+			if entry.Length == 0 {
+				continue
+			}
+			chunks = append(chunks, types.Vec2u{
+				X: rx*config.RegionSize + uint64(i%config.RegionSize),
+				Y: ry*config.RegionSize + uint64(i/config.RegionSize),
+			})
+		}
+	}
+	return chunks, nil
+}
+
+// Moves chunks saved in the old format (one chunk_X_Y.gob file per chunk) into region files.
+// Does nothing if the world doesn't have any of those.
+func convertLegacyChunks(metadata types.Save) {
+	legacyChunks, err := filepath.Glob(filepath.Join(saveDirectory(metadata), "chunk_*_*.gob"))
+	if err != nil || len(legacyChunks) == 0 {
+		return
+	}
+
+	for _, path := range legacyChunks {
+		var cx, cy uint64
+		if _, err := fmt.Sscanf(filepath.Base(path), "chunk_%d_%d.gob", &cx, &cy); err != nil {
+			log.Printf("convertLegacyChunks() - skipping %v: %v", path, err)
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Panicf("convertLegacyChunks() - failed to read %v: %v", path, err)
+		}
+		if err := writeChunkRecord(metadata, cx, cy, data); err != nil {
+			log.Panicf("convertLegacyChunks() - failed to write chunk %v, %v to the region: %v", cx, cy, err)
+		}
+		os.Remove(path)
+	}
+
+	log.Printf("convertLegacyChunks() - moved %v chunks of world %v to region files", len(legacyChunks), metadata.UUID)
+}
@@ -0,0 +1,155 @@
+// Package render draws the world with ebiten.
+// It is separate from package world, so that worlds can be generated, loaded and saved
+// without a graphics context (e.g. in command-line tools).
+package render
+
+import (
+	"math"
+
+	"github.com/3elDU/bamboo/colors"
+	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/font"
+	"github.com/3elDU/bamboo/textures"
+	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/world"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type WorldRenderer struct {
+	// Textures of the chunks, that were drawn in the last frame.
+	// Other textures are dropped, so unloaded chunks don't keep their textures.
+	chunkTextures map[*world.Chunk]*ebiten.Image
+}
+
+func NewWorldRenderer() *WorldRenderer {
+	return &WorldRenderer{
+		chunkTextures: make(map[*world.Chunk]*ebiten.Image),
+	}
+}
+
+func BlockToScreen(screen *ebiten.Image, player types.Vec2f, block types.Vec2u, scaling float64) types.Vec2f {
+	screenWidth, screenHeight := screen.Bounds().Dx(), screen.Bounds().Dy()
+	return types.Vec2f{
+		X: (float64(block.X)-player.X)*16 + float64(screenWidth)/2 - (float64(screenWidth)/scaling*(scaling-1))/2,
+		Y: (float64(block.Y)-player.Y)*16 + float64(screenHeight)/2 - (float64(screenHeight)/scaling*(scaling-1))/2,
+	}
+}
+
+func (renderer *WorldRenderer) Render(screen *ebiten.Image, w *world.World, playerX, playerY, scaling float64) {
+	screenWidth, screenHeight := screen.Bounds().Dx(), screen.Bounds().Dy()
+	screenWidthInChunks := float64(screenWidth) / 256 / scaling
+	screenHeightInChunks := float64(screenHeight) / 256 / scaling
+	opts := &ebiten.DrawImageOptions{}
+
+	// Adjust camera position to show the right area
+	cameraOffsetX := screenWidthInChunks / 2 * 16
+	cameraOffsetY := screenHeightInChunks / 2 * 16
+
+	drawn := make(map[*world.Chunk]*ebiten.Image)
+	for x := playerX - cameraOffsetX - 16; x < playerX+cameraOffsetX+16; x += 16 {
+		for y := playerY - cameraOffsetY - 16; y < playerY+cameraOffsetY+16; y += 16 {
+			// Skip chunks that are out of world borders
+			if x < 0 || x >= float64(w.Metadata().Size.X) || y < 0 || y >= float64(w.Metadata().Size.Y) {
+				continue
+			}
+
+			chunk := w.ChunkAtB(uint64(x), uint64(y)).(*world.Chunk)
+			needsRedraw, _ := chunk.NeedsRedraw()
+			texture := renderer.renderChunk(w, chunk)
+			drawn[chunk] = texture
+
+			screenX := (x - playerX - math.Mod(x, 16)) * 16
+			screenX += float64(screenWidth)/2 - (float64(screenWidth)/scaling*(scaling-1))/2
+			screenY := (y - playerY - math.Mod(y, 16)) * 16
+			screenY += float64(screenHeight)/2 - (float64(screenHeight)/scaling*(scaling-1))/2
+
+			opts.GeoM.Reset()
+			opts.GeoM.Translate(screenX, screenY)
+			opts.GeoM.Scale(scaling, scaling)
+			screen.DrawImage(texture, opts)
+
+			if config.DebugMode && needsRedraw {
+				font.RenderFontWithOptions(screen, "REDRAW", screenX*scaling, screenY*scaling, colors.C("red"), 1.0, false)
+			}
+		}
+	}
+
+	for chunk, texture := range renderer.chunkTextures {
+		if _, stillDrawn := drawn[chunk]; !stillDrawn {
+			texture.Dispose()
+		}
+	}
+	renderer.chunkTextures = drawn
+}
+
+// Returns the texture of the chunk, redrawing it if needed
+func (renderer *WorldRenderer) renderChunk(w types.World, chunk *world.Chunk) *ebiten.Image {
+	texture, exists := renderer.chunkTextures[chunk]
+	needsRedraw, recursiveRedraw := chunk.NeedsRedraw()
+	// do not redraw a chunk, when there is no need to
+	if exists && !needsRedraw {
+		return texture
+	}
+	if !exists {
+		texture = ebiten.NewImage(256, 256)
+	}
+
+	for x := uint(0); x < 16; x++ {
+		for y := uint(0); y < 16; y++ {
+			drawableBlock, ok := chunk.At(x, y).(types.DrawableBlock)
+			if !ok {
+				continue
+			}
+
+			renderBlock(w, texture, drawableBlock, types.Vec2f{
+				X: float64(x) * 16,
+				Y: float64(y) * 16,
+			}, recursiveRedraw)
+		}
+	}
+
+	chunk.Redrawn()
+	return texture
+}
+
+// recursiveRedraw means that a block can trigger a redraw of other chunks
+// if it is set to false, the block shouldn't attempt to trigger redraw of other chunks
+func renderBlock(w types.World, screen *ebiten.Image, block types.DrawableBlock, pos types.Vec2f, recursiveRedraw bool) {
+	opts := &ebiten.DrawImageOptions{}
+
+	connectedBlock, connected := block.(types.ConnectedBlock)
+	if !connected {
+		texture := textures.Texture(block.TextureName())
+		if rotation := block.TextureRotation(); rotation != 0 {
+			bounds := texture.Bounds()
+			// Move image half a texture size, so that rotation origin will be in the center
+			opts.GeoM.Translate(float64(-bounds.Dx()/2), float64(-bounds.Dy()/2))
+			opts.GeoM.Rotate(rotation * (math.Pi / 180))
+			pos.X += float64(bounds.Dx() / 2)
+			pos.Y += float64(bounds.Dy() / 2)
+		}
+
+		opts.GeoM.Translate(pos.X, pos.Y)
+		screen.DrawImage(texture, opts)
+		return
+	}
+
+	coords := block.Coords()
+	var connectedSides [4]bool
+	for i, side := range [4]types.Vec2i{{X: -1, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: 0, Y: 1}} {
+		x, y := int(coords.X)+side.X, int(coords.Y)+side.Y
+		neighbor := w.BlockAt(uint64(x), uint64(y))
+		if !connectedBlock.ShouldConnect(neighbor.Type()) {
+			continue
+		}
+
+		connectedSides[i] = true
+		// If neighbor is on another chunk, trigger a non-recursive redraw of that chunk
+		if recursiveRedraw && neighbor.ParentChunk() != block.ParentChunk() {
+			neighbor.ParentChunk().TriggerRedraw(false)
+		}
+	}
+
+	opts.GeoM.Translate(pos.X, pos.Y)
+	screen.DrawImage(textures.Connected(block.TextureName(), connectedSides), opts)
+}
@@ -3,6 +3,7 @@
 package world
 
 import (
+	"bytes"
 	"encoding/gob"
 	"fmt"
 	"log"
@@ -11,36 +12,42 @@ import (
 
 	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/util"
 	"github.com/google/uuid"
 )
 
-func init() {
-	// create saves directory, if it doesn't exist yet
-	os.Mkdir(config.WorldSaveDirectory, os.ModePerm)
-}
-
 // SaverLoader maintains chunk loading/saving queue,
 // while doing actual work on separate goroutine,
 // so we don't have any freezes on the main thread
 type SaverLoader struct {
 	Metadata types.Save
+	// Used to regenerate chunks that were found corrupted
+	generator types.WorldGenerator
 
 	saveRequests     chan Chunk
 	loadRequestsPool map[types.Vec2u]bool
 	// loadRequestsPool keeps track of currently requested chunks,
 	// so that one same chunk can't be requested twice
 	loadRequests chan types.Vec2u
-	loaded       chan *Chunk
+	loaded       chan loadResult
 }
 
-func NewWorldSaverLoader(metadata types.Save) *SaverLoader {
+// Result of a load request. Chunk is nil, if the chunk couldn't be loaded,
+// so the request is dropped from the pool, and the chunk can be requested again
+type loadResult struct {
+	coords types.Vec2u
+	chunk  *Chunk
+}
+
+func NewWorldSaverLoader(metadata types.Save, generator types.WorldGenerator) *SaverLoader {
 	return &SaverLoader{
-		Metadata: metadata,
+		Metadata:  metadata,
+		generator: generator,
 
 		saveRequests:     make(chan Chunk, 1024),
 		loadRequestsPool: make(map[types.Vec2u]bool),
 		loadRequests:     make(chan types.Vec2u, 256),
-		loaded:           make(chan *Chunk),
+		loaded:           make(chan loadResult),
 	}
 }
 
@@ -57,13 +64,19 @@ func (sl *SaverLoader) runLoader() {
 	for {
 		request := <-sl.loadRequests
 
-		c := LoadChunk(sl.Metadata, request.X, request.Y)
-		if c == nil {
-			// if the requested chunk doesn't exist, simply ignore the error and skip it
-			continue
+		c, err := LoadChunk(sl.Metadata, request.X, request.Y)
+		if corrupt, ok := err.(*CorruptChunkError); ok {
+			// move the broken chunk out of the way, and generate it from scratch.
+			// The generator workers do it, and the world receives it from the generator, like any new chunk
+			log.Printf("SaverLoader - %v; regenerating", corrupt)
+			quarantineChunk(sl.Metadata, corrupt)
+			sl.generator.Generate(NewChunk(request.X, request.Y))
+		} else if err != nil {
+			log.Printf("SaverLoader - failed to load chunk %v, %v: %v", request.X, request.Y, err)
 		}
+		// if the requested chunk doesn't exist, c is nil, and the request is simply dropped
 
-		sl.loaded <- c
+		sl.loaded <- loadResult{coords: request, chunk: c}
 	}
 }
 
@@ -75,12 +88,17 @@ func (sl *SaverLoader) Run() {
 // Returns newly loaded chunk
 // If there is no pending chunks, returns nil
 func (sl *SaverLoader) Receive() *Chunk {
-	select {
-	case c := <-sl.loaded:
-		delete(sl.loadRequestsPool, c.Coords())
-		return c
-	default:
-		return nil
+	for {
+		select {
+		case result := <-sl.loaded:
+			delete(sl.loadRequestsPool, result.coords)
+			if result.chunk == nil {
+				continue
+			}
+			return result.chunk
+		default:
+			return nil
+		}
 	}
 }
 
@@ -113,58 +131,124 @@ type SavedBlock struct {
 // represents chunk on the disk
 // all chunks are converted to this structure before saving
 type SavedChunk struct {
+	// Save format version the chunk was saved with
+	Version int
+
 	X, Y uint64
 	Data [16][16]SavedBlock
 }
 
-func Load(baseID, id uuid.UUID) *World {
-	saveDir := filepath.Join(config.WorldSaveDirectory, baseID.String(), id.String())
+// Directory where the world with given metadata is stored
+func saveDirectory(metadata types.Save) string {
+	return filepath.Join(config.WorldSaveDirectory, metadata.BaseUUID.String(), metadata.UUID.String())
+}
+
+func metadataPath(baseID, id uuid.UUID) string {
+	return filepath.Join(config.WorldSaveDirectory, baseID.String(), id.String(), config.WorldInfoFile)
+}
+
+// ReadMetadata reads metadata of the world, without loading the world itself
+func ReadMetadata(baseID, id uuid.UUID) (types.Save, error) {
+	var metadata types.Save
+
+	data, err := os.ReadFile(metadataPath(baseID, id))
+	if err != nil {
+		return metadata, err
+	}
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(&metadata)
+	return metadata, err
+}
+
+// ListWorlds returns metadata of all worlds in the save: the overworld and all caves.
+// Worlds, which metadata can't be read, are skipped.
+func ListWorlds(baseID uuid.UUID) ([]types.Save, error) {
+	entries, err := os.ReadDir(filepath.Join(config.WorldSaveDirectory, baseID.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	worlds := make([]types.Save, 0)
+	for _, entry := range entries {
+		// each world has its own folder, named after its UUID
+		id, err := uuid.Parse(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		metadata, err := ReadMetadata(baseID, id)
+		if err != nil {
+			log.Printf("ListWorlds() - skipping world %v: %v", id, err)
+			continue
+		}
+		worlds = append(worlds, metadata)
+	}
+	return worlds, nil
+}
 
-	f, err := os.Open(filepath.Join(saveDir, config.WorldInfoFile))
+// Load returns an error if world metadata is missing or can't be decoded.
+// Corrupted metadata is moved to the quarantine.
+func Load(baseID, id uuid.UUID) (*World, error) {
+	data, err := os.ReadFile(metadataPath(baseID, id))
 	if err != nil {
-		log.Panicf("world.Load() - invalid file descriptor - %v", err)
+		return nil, fmt.Errorf("world.Load() - failed to read metadata - %v", err)
 	}
 
-	decoder := gob.NewDecoder(f)
 	metadata := new(types.Save)
-	if err := decoder.Decode(metadata); err != nil {
-		log.Panicf("world.Load() - failed to decode metadata - %v", err)
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(metadata); err != nil {
+		QuarantineFile(baseID, fmt.Sprintf("%v_%v", id, config.WorldInfoFile), data)
+		return nil, fmt.Errorf("world.Load() - failed to decode metadata - %v", err)
+	}
+
+	if metadata.Version > config.SaveFormatVersion {
+		return nil, fmt.Errorf("world.Load() - world was %w %v", ErrNewerSaveVersion, metadata.Version)
+	}
+	if metadata.Version < config.SaveFormatVersion {
+		// chunks keep their own version, so they are migrated separately, when loaded.
+		// The metadata itself is stamped with the current version in NewWorld()
+		log.Printf("world.Load() - upgrading save format from version %v to %v", metadata.Version, config.SaveFormatVersion)
 	}
 
 	log.Printf("world.Load() - loaded metadata; seed - %v", metadata.Seed)
 
-	return NewWorld(*metadata)
+	return NewWorld(*metadata), nil
 }
 
 // NOTE: world folder is named after the UUID, not after the world name
 // that is, to avoid folder collision
 func (world *World) Save() {
-	saveDir := filepath.Join(config.WorldSaveDirectory, world.metadata.BaseUUID.String(), world.metadata.UUID.String())
+	files := util.NewAtomicWriter()
+	world.SaveTo(files)
+	if err := files.Commit(); err != nil {
+		log.Panicf("failed to write world metadata - %v", err)
+	}
+}
+
+// SaveTo saves all modified chunks, and adds world metadata to the given writer.
+// The metadata is not written until files.Commit() is called,
+// so it can be committed together with other files of the save.
+func (world *World) SaveTo(files *util.AtomicWriter) {
+	saveDir := saveDirectory(world.metadata)
 
 	// make a save directory, if it doesn't exist yet
 	os.MkdirAll(saveDir, os.ModePerm)
 
-	// open world metadata file
-	f, err := os.Create(filepath.Join(saveDir, config.WorldInfoFile))
-	if err != nil {
-		log.Panicf("failed to create world metadata file")
+	// loop over all loaded chunks, saving modified ones to the disk
+	for _, chunk := range world.chunks {
+		chunk.Save(world.metadata)
 	}
-	defer f.Close()
 
-	// encode the metadata to it
-	encoder := gob.NewEncoder(f)
-	if err := encoder.Encode(world.metadata); err != nil {
+	// encode the metadata
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(world.metadata); err != nil {
 		log.Panicf("failed to encode world metadata")
 	}
-
-	// loop over all loaded chunks, saving modified ones to the disk
-	for _, chunk := range world.chunks {
-		chunk.Save(world.metadata)
+	if err := files.Add(filepath.Join(saveDir, config.WorldInfoFile), buf.Bytes()); err != nil {
+		log.Panicf("failed to create world metadata file - %v", err)
 	}
 }
 
 func ExistsOnDisk(metadata types.Save) bool {
-	path := filepath.Join(config.WorldSaveDirectory, metadata.BaseUUID.String(), metadata.UUID.String())
+	path := saveDirectory(metadata)
 
 	file, err := os.Open(path)
 	defer file.Close()
@@ -176,65 +260,86 @@ func ExistsOnDisk(metadata types.Save) bool {
 }
 
 func ChunkExistsOnDisk(metadata types.Save, x, y uint64) bool {
-	path := filepath.Join(config.WorldSaveDirectory, metadata.BaseUUID.String(), metadata.UUID.String(),
-		fmt.Sprintf("chunk_%v_%v.gob", x, y))
-
-	_, err := os.Stat(path)
-	return err == nil
+	return chunkRecordExists(metadata, x, y)
 }
 
-// if saved chunk doesn't exist, returns nil
-func LoadChunk(metadata types.Save, x, y uint64) *Chunk {
-	path := filepath.Join(config.WorldSaveDirectory, metadata.BaseUUID.String(), metadata.UUID.String(),
-		fmt.Sprintf("chunk_%v_%v.gob", x, y))
+// if saved chunk doesn't exist, returns nil without an error.
+// If the chunk is damaged, returns *CorruptChunkError.
+func LoadChunk(metadata types.Save, x, y uint64) (*Chunk, error) {
+	savedChunk, data, err := readSavedChunk(metadata, x, y)
+	if err != nil || savedChunk == nil {
+		return nil, err
+	}
 
-	if _, err := os.Stat(path); err == nil {
-		f, err := os.Open(path)
-		if err != nil {
-			log.Panicf("failed to open a chunk - %v", err)
-		}
+	c, err := loadSavedChunk(savedChunk)
+	if err != nil {
+		return nil, &CorruptChunkError{X: x, Y: y, Data: data, Err: err}
+	}
+	return c, nil
+}
 
-		savedChunk := new(SavedChunk)
-		if err := gob.NewDecoder(f).Decode(savedChunk); err != nil {
-			log.Panicf("failed to decode a chunk - %v", err)
-		}
+// ReadSavedChunk returns the chunk as it is stored on the disk, without loading block states.
+// Like LoadChunk(), returns nil without an error if the chunk doesn't exist.
+func ReadSavedChunk(metadata types.Save, x, y uint64) (*SavedChunk, error) {
+	savedChunk, _, err := readSavedChunk(metadata, x, y)
+	return savedChunk, err
+}
 
-		c := NewChunk(x, y)
+// Also returns the raw chunk record, so it can be quarantined
+func readSavedChunk(metadata types.Save, x, y uint64) (*SavedChunk, []byte, error) {
+	data, err := readChunkRecord(metadata, x, y)
+	if err != nil {
+		return nil, nil, err
+	}
+	if data == nil {
+		return nil, nil, nil
+	}
 
-		// decode blocks
-		for x := uint(0); x < 16; x++ {
-			for y := uint(0); y < 16; y++ {
-				b := types.NewBlock(savedChunk.Data[x][y].Type)
-				b.LoadState(savedChunk.Data[x][y].State)
-				c.SetBlock(x, y, b)
-			}
-		}
+	savedChunk, err := decodeChunk(data)
+	if err != nil {
+		return nil, data, &CorruptChunkError{X: x, Y: y, Data: data, Err: fmt.Errorf("failed to decode a chunk - %v", err)}
+	}
+	if savedChunk.Version > config.SaveFormatVersion {
+		return nil, data, fmt.Errorf("chunk %v, %v was saved with newer save format version %v", x, y, savedChunk.Version)
+	}
 
-		// mark chunk as unmodified, to avoid recursive loading/saving
-		c.modified = false
-		return c
+	if savedChunk.X != x || savedChunk.Y != y {
+		return nil, data, &CorruptChunkError{X: x, Y: y, Data: data, Err: fmt.Errorf("record belongs to chunk %v, %v", savedChunk.X, savedChunk.Y)}
 	}
 
-	return nil
+	return savedChunk, data, nil
 }
 
-func (c *Chunk) Save(metadata types.Save) {
-	if !c.modified || c.preventSaving {
-		return
-	}
+func loadSavedChunk(savedChunk *SavedChunk) (c *Chunk, err error) {
+	// blocks panic in LoadState(), if the state has unexpected type
+	defer func() {
+		if r := recover(); r != nil {
+			c, err = nil, fmt.Errorf("failed to load block state - %v", r)
+		}
+	}()
 
-	path := filepath.Join(config.WorldSaveDirectory, metadata.BaseUUID.String(), metadata.UUID.String(),
-		fmt.Sprintf("chunk_%v_%v.gob", c.x, c.y))
+	c = NewChunk(savedChunk.X, savedChunk.Y)
 
-	f, err := os.Create(path)
-	if err != nil {
-		log.Panicf("failed to create chunk save file - %v", err)
+	// decode blocks
+	for x := uint(0); x < 16; x++ {
+		for y := uint(0); y < 16; y++ {
+			saved := savedChunk.Data[x][y]
+			b := types.NewBlock(saved.Type)
+			b.LoadState(types.MigrateBlockState(savedChunk.Version, saved.Type, saved.State))
+			c.SetBlock(x, y, b)
+		}
 	}
-	defer f.Close()
 
-	// serialize the chunk
+	// mark chunk as unmodified, to avoid recursive loading/saving
+	c.modified = false
+	return c, nil
+}
+
+// Converts the chunk to the structure, that is stored on the disk
+func (c *Chunk) toSaved() SavedChunk {
 	chunk := SavedChunk{
-		X: c.x, Y: c.y,
+		Version: config.SaveFormatVersion,
+		X:       c.x, Y: c.y,
 	}
 	for x := 0; x < 16; x++ {
 		for y := 0; y < 16; y++ {
@@ -245,10 +350,21 @@ func (c *Chunk) Save(metadata types.Save) {
 			}
 		}
 	}
+	return chunk
+}
+
+func (c *Chunk) Save(metadata types.Save) {
+	if !c.modified || c.preventSaving {
+		return
+	}
+
+	record, err := encodeChunk(c.toSaved(), worldCodec(metadata))
+	if err != nil {
+		log.Panicf("failed to encode chunk - %v", err)
+	}
 
-	encoder := gob.NewEncoder(f)
-	if err := encoder.Encode(chunk); err != nil {
-		log.Panicf("failed to encode chunk")
+	if err := writeChunkRecord(metadata, c.x, c.y, record); err != nil {
+		log.Panicf("failed to write chunk to the region file - %v", err)
 	}
 
 	c.modified = false
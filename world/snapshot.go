@@ -0,0 +1,218 @@
+// Snapshots are backup copies of a whole save, including all caves
+
+package world
+
+import (
+	"archive/zip"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/util"
+	"github.com/google/uuid"
+)
+
+// Used as the file name of the snapshot
+const snapshotTimeFormat = "2006-01-02_15-04-05.000"
+
+type Snapshot struct {
+	BaseUUID uuid.UUID
+	Time     time.Time
+	// Path to the snapshot archive
+	Path string
+	// Size of the archive in bytes
+	Size int64
+}
+
+// Snapshots are kept outside of the saves folder,
+// so they survive deletion of the world
+func snapshotDirectory(baseUUID uuid.UUID) string {
+	return filepath.Join(config.SnapshotDirectory, baseUUID.String())
+}
+
+// SnapshotProgress tracks how much of the save was archived so far.
+// It is updated by CreateSnapshot, and can be read from other goroutines.
+type SnapshotProgress struct {
+	done, total int64
+}
+
+// Bytes returns the amount of bytes archived, and the total size of the save
+func (progress *SnapshotProgress) Bytes() (done, total int64) {
+	return atomic.LoadInt64(&progress.done), atomic.LoadInt64(&progress.total)
+}
+
+// Percent returns how much of the save was archived, from 0 to 100
+func (progress *SnapshotProgress) Percent() int {
+	done, total := progress.Bytes()
+	if total == 0 {
+		return 0
+	}
+	return int(done * 100 / total)
+}
+
+// CreateSnapshot archives the current state of the save on the disk.
+// Chunks that are loaded, but not saved yet, are not included.
+// Progress is optional.
+func CreateSnapshot(baseUUID uuid.UUID, progress *SnapshotProgress) (Snapshot, error) {
+	saveDir := filepath.Join(config.WorldSaveDirectory, baseUUID.String())
+	dir := snapshotDirectory(baseUUID)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return Snapshot{}, err
+	}
+
+	var onWritten func(int64)
+	if progress != nil {
+		total, err := util.ZipDirectorySize(saveDir)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		atomic.StoreInt64(&progress.total, total)
+		onWritten = func(written int64) {
+			atomic.AddInt64(&progress.done, written)
+		}
+	}
+
+	now := time.Now()
+	path := filepath.Join(dir, now.Format(snapshotTimeFormat)+".zip")
+
+	// write the archive to a temporary file first, so half-written snapshots never show up in the list
+	f, err := os.CreateTemp(dir, "snapshot.*.tmp")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath)
+
+	archive := zip.NewWriter(f)
+	if err := util.ZipDirectory(archive, saveDir, "", onWritten); err != nil {
+		f.Close()
+		return Snapshot{}, err
+	}
+	if err := archive.Close(); err != nil {
+		f.Close()
+		return Snapshot{}, err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return Snapshot{}, err
+	}
+	if err := f.Close(); err != nil {
+		return Snapshot{}, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return Snapshot{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	log.Printf("CreateSnapshot() - created snapshot %v", path)
+	return Snapshot{BaseUUID: baseUUID, Time: now, Path: path, Size: info.Size()}, nil
+}
+
+// ListSnapshots returns snapshots of the save, newest first
+func ListSnapshots(baseUUID uuid.UUID) []Snapshot {
+	entries, err := os.ReadDir(snapshotDirectory(baseUUID))
+	if err != nil {
+		return nil
+	}
+
+	snapshots := make([]Snapshot, 0)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".zip") {
+			continue
+		}
+
+		t, err := time.ParseInLocation(snapshotTimeFormat, strings.TrimSuffix(name, ".zip"), time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		snapshots = append(snapshots, Snapshot{
+			BaseUUID: baseUUID,
+			Time:     t,
+			Path:     filepath.Join(snapshotDirectory(baseUUID), name),
+			Size:     info.Size(),
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.After(snapshots[j].Time)
+	})
+	return snapshots
+}
+
+// RestoreSnapshot replaces the save with the contents of the snapshot.
+// The world must not be loaded while it is restored.
+func RestoreSnapshot(snapshot Snapshot) error {
+	saveDir := filepath.Join(config.WorldSaveDirectory, snapshot.BaseUUID.String())
+	// Both directories are kept in the snapshot folder,
+	// so the world list doesn't pick them up while restoring
+	restoreDir := filepath.Join(snapshotDirectory(snapshot.BaseUUID), "restoring")
+	replacedDir := filepath.Join(snapshotDirectory(snapshot.BaseUUID), "replaced")
+	os.RemoveAll(restoreDir)
+	os.RemoveAll(replacedDir)
+
+	archive, err := zip.OpenReader(snapshot.Path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	if err := util.UnzipDirectory(&archive.Reader, restoreDir, ""); err != nil {
+		os.RemoveAll(restoreDir)
+		return fmt.Errorf("failed to extract snapshot - %v", err)
+	}
+
+	// move the current save out of the way, and put the restored one in its place
+	if err := os.Rename(saveDir, replacedDir); err != nil && !os.IsNotExist(err) {
+		os.RemoveAll(restoreDir)
+		return err
+	}
+	// the saves folder is gone too, if the world was deleted
+	if err := os.MkdirAll(config.WorldSaveDirectory, os.ModePerm); err != nil {
+		os.RemoveAll(restoreDir)
+		return err
+	}
+	if err := os.Rename(restoreDir, saveDir); err != nil {
+		// put the old save back
+		os.Rename(replacedDir, saveDir)
+		return err
+	}
+	os.RemoveAll(replacedDir)
+
+	log.Printf("RestoreSnapshot() - restored %v from %v", snapshot.BaseUUID, snapshot.Path)
+	return nil
+}
+
+func DeleteSnapshot(snapshot Snapshot) error {
+	return os.Remove(snapshot.Path)
+}
+
+// PruneSnapshots deletes all snapshots of the save, except for the given number of newest ones
+func PruneSnapshots(baseUUID uuid.UUID, keep int) error {
+	snapshots := ListSnapshots(baseUUID)
+	if len(snapshots) <= keep {
+		return nil
+	}
+
+	for _, snapshot := range snapshots[keep:] {
+		if err := DeleteSnapshot(snapshot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
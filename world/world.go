@@ -7,8 +7,8 @@ import (
 	"github.com/3elDU/bamboo/worldgen"
 	"golang.org/x/exp/slices"
 
+	"github.com/3elDU/bamboo/clock"
 	"github.com/3elDU/bamboo/config"
-	"github.com/3elDU/bamboo/scene_manager"
 	"github.com/3elDU/bamboo/types"
 )
 
@@ -27,6 +27,10 @@ func SizeForWorldType(world world_type.WorldType) types.Vec2u {
 		return types.Vec2u{X: config.OverworldSize, Y: config.OverworldSize}
 	case world_type.Cave:
 		return types.Vec2u{X: config.Cave1Size, Y: config.Cave1Size}
+	case world_type.Cave2:
+		return types.Vec2u{X: config.Cave2Size, Y: config.Cave2Size}
+	case world_type.Cave3:
+		return types.Vec2u{X: config.Cave3Size, Y: config.Cave3Size}
 	}
 
 	log.Printf("Unable to retrieve world size for world type %v", world)
@@ -36,10 +40,20 @@ func SizeForWorldType(world world_type.WorldType) types.Vec2u {
 func NewWorld(metadata types.Save) *World {
 	log.Printf("NewWorld - %v", metadata)
 
+	// worlds saved by older versions store each chunk in a separate file
+	convertLegacyChunks(metadata)
+	// everything is saved using the current save format
+	metadata.Version = config.SaveFormatVersion
+	// worlds created before codecs were selectable per world use the default one
+	metadata.Codec = worldCodec(metadata)
+	if !CodecExists(metadata.Codec) {
+		log.Panicf("NewWorld - unknown chunk codec %v", metadata.Codec)
+	}
+
 	generator := worldgen.NewWorldgenForWorld(metadata)
-	go generator.Run()
+	generator.Run()
 
-	saverLoader := NewWorldSaverLoader(metadata)
+	saverLoader := NewWorldSaverLoader(metadata, generator)
 	go saverLoader.Run()
 
 	return &World{
@@ -53,6 +67,12 @@ func NewWorld(metadata types.Save) *World {
 }
 
 func (world *World) Update() {
+	// generate chunks around the player first
+	if player := types.GetCurrentPlayer(); player != nil {
+		pos := player.Position()
+		world.generator.SetPriorityCenter(types.Vec2u{X: uint64(pos.X) / 16, Y: uint64(pos.Y) / 16})
+	}
+
 	// receive newly generated chunks from world generator
 	chunks := world.generator.Receive()
 	for _, chunk := range chunks {
@@ -79,11 +99,13 @@ func (world *World) Update() {
 	// each 30 ticks ( half a second ) check for chunks,
 	// that weren't accessed ( neither read, nor write ) for specified amount of ticks
 	// ( check config.go )
-	if scene_manager.Ticks()%30 == 0 {
+	if clock.Ticks()%30 == 0 {
 		for coords, chunk := range world.chunks {
-			if scene_manager.Ticks()-chunk.lastAccessed > config.ChunkUnloadDelay {
+			if clock.Ticks()-chunk.lastAccessed > config.ChunkUnloadDelay {
 				world.saverLoader.Save(chunk)
 				delete(world.chunks, coords)
+				// if the chunk is still waiting to be generated, it isn't needed anymore
+				world.generator.Cancel(coords.X, coords.Y)
 			}
 		}
 	}
@@ -133,7 +155,7 @@ func (world *World) ChunkAtB(bx, by uint64) types.Chunk {
 		world.chunks[chunkCoordinates] = dummyChunk
 	}
 
-	world.chunks[chunkCoordinates].lastAccessed = scene_manager.Ticks()
+	world.chunks[chunkCoordinates].lastAccessed = clock.Ticks()
 	return world.chunks[chunkCoordinates]
 }
 
@@ -142,7 +164,7 @@ func (world *World) BlockAt(bx, by uint64) types.Block {
 
 	chunk, exists := world.chunks[types.Vec2u{X: cx, Y: cy}]
 	if !exists {
-		return types.NewEmptyBlock()
+		return types.NewBlock(types.EmptyBlock)
 	}
 
 	return chunk.At(uint(bx%16), uint(by%16))
@@ -260,3 +282,9 @@ func (world *World) Metadata() types.Save {
 func (world *World) Generator() types.WorldGenerator {
 	return world.generator
 }
+
+// Stops the world generator. Must be called, when the world is no longer used,
+// after it was saved, so generation workers don't keep running in the background
+func (world *World) Unload() {
+	world.generator.Stop()
+}
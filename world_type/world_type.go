@@ -4,5 +4,42 @@ type WorldType int
 
 const (
 	Overworld WorldType = iota
+	// First cave tier, entered from the overworld
 	Cave
+	// Deeper cave tiers, entered through descents in the tier above
+	Cave2
+	Cave3
 )
+
+var worldTypeNames = [...]string{
+	Overworld: "overworld",
+	Cave:      "cave",
+	Cave2:     "cave2",
+	Cave3:     "cave3",
+}
+
+func (t WorldType) String() string {
+	if t < 0 || int(t) >= len(worldTypeNames) {
+		return "unknown"
+	}
+	return worldTypeNames[t]
+}
+
+// How deep underground the world is. The overworld is 0, caves start from 1
+func (t WorldType) Depth() int {
+	return int(t)
+}
+
+// Returns true for all cave tiers
+func (t WorldType) IsCave() bool {
+	return t >= Cave && t <= Cave3
+}
+
+// Returns the cave tier below this one.
+// ok is false for the deepest tier
+func (t WorldType) Deeper() (deeper WorldType, ok bool) {
+	if t >= Cave3 {
+		return t, false
+	}
+	return t + 1, true
+}
@@ -1,11 +1,15 @@
 package worldgen
 
 import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+
 	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/util"
 	"github.com/aquilax/go-perlin"
-	"math"
-	"math/rand"
 )
 
 // Features are regular random numbers, that can be used while generating blocks.
@@ -36,10 +40,12 @@ func makeFeatures(p *perlin.Perlin, bx, by uint64) BlockFeatures {
 // Applies circular mask to generated perlin noise
 // The further block is from the center, the stronger the mask will be
 // This makes the world look like an archipelago, surrounded by ocean on all sides,
-// not like an infinite number of islands
-func applyCircularMask(worldSize types.Vec2u, x, y, val float64) float64 {
+// not like an infinite number of islands.
+// Radius of the mask is the average side of the world divided by radiusDivisor,
+// and falloff makes the mask weaker
+func applyCircularMask(worldSize types.Vec2u, radiusDivisor, falloff, x, y, val float64) float64 {
 	var (
-		radius  = (float64(worldSize.X) + float64(worldSize.Y)) / 2 / 2.5
+		radius  = (float64(worldSize.X) + float64(worldSize.Y)) / 2 / radiusDivisor
 		centerX = float64(worldSize.X) / 2
 		centerY = float64(worldSize.Y) / 2
 	)
@@ -50,8 +56,47 @@ func applyCircularMask(worldSize types.Vec2u, x, y, val float64) float64 {
 	}
 
 	distanceToCenter := math.Sqrt(math.Pow(x-centerX, 2) + math.Pow(y-centerY, 2))
-	// Divide the mask by 1.5, so it won't be too big
-	mask := distanceToCenter / radius / 1.5
+	// Divide the mask, so it won't be too big
+	mask := distanceToCenter / radius / falloff
+	return val - mask
+}
+
+// Part of the continent (from the center to the edge), that isn't affected by the mask
+const ContinentInland = 0.6
+
+// Applies the mask, that forms the shape of the land
+func applyMask(worldSize types.Vec2u, shape types.WorldShape, radiusDivisor, falloff, x, y, val float64) float64 {
+	switch shape {
+	case types.ArchipelagoShape:
+		// each cell of the grid gets its own circular mask, as if it was a separate world
+		cellSize := types.Vec2u{X: worldSize.X / types.IslandGrid, Y: worldSize.Y / types.IslandGrid}
+		cellX := math.Min(math.Floor(x/float64(cellSize.X)), types.IslandGrid-1)
+		cellY := math.Min(math.Floor(y/float64(cellSize.Y)), types.IslandGrid-1)
+		return applyCircularMask(cellSize, radiusDivisor, falloff,
+			x-cellX*float64(cellSize.X), y-cellY*float64(cellSize.Y), val,
+		)
+	case types.ContinentShape:
+		return applyContinentMask(worldSize, falloff, x, y, val)
+	default:
+		return applyCircularMask(worldSize, radiusDivisor, falloff, x, y, val)
+	}
+}
+
+// Continent fills the world almost up to its edges, so the mask is a rounded square
+// instead of a circle, and it only starts near the coast
+func applyContinentMask(worldSize types.Vec2u, falloff, x, y, val float64) float64 {
+	var (
+		radiusX = float64(worldSize.X) / 2
+		radiusY = float64(worldSize.Y) / 2
+	)
+
+	// superellipse, distance is 1 at the edges of the world, and a bit further in the corners
+	distance := math.Pow(math.Pow(math.Abs(x-radiusX)/radiusX, 4)+math.Pow(math.Abs(y-radiusY)/radiusY, 4), 0.25)
+	if distance >= 1 {
+		return 0
+	}
+
+	mask := math.Max(0, distance-ContinentInland) / (1 - ContinentInland) / falloff
 	return val - mask
 }
 
@@ -68,49 +113,163 @@ type generatorImplementation interface {
 	seed() int64
 }
 
+// Implemented by generators of worlds with biomes
+type biomeImplementation interface {
+	biomeAt(x, y uint64) Biome
+}
+
 // Generator handles chunk generation queue, while the generation itself is handled by embedded class.
-// Generation runs in separate goroutine to reduce freezes
+// Generation runs on a pool of worker goroutines to reduce freezes
 type Generator struct {
-	// requestsPool keeps track of currently requested chunks,
-	// so that one same chunk can't be requested twice
-	requestsPool map[types.Vec2u]types.Chunk
-	requests     chan types.Vec2u
-	generated    chan types.Chunk
+	// mutex guards everything below, up to the implementation
+	mutex sync.Mutex
+	// signalled when a new request is added to the queue
+	newRequest *sync.Cond
+
+	// requests, that weren't picked up by a worker yet
+	pending map[types.Vec2u]types.Chunk
+	// requests, that are being generated right now.
+	// The value is true, if the request was cancelled while it was generated
+	inProgress map[types.Vec2u]bool
+	generated  []types.Chunk
+	// requests closest to this point are picked first
+	priorityCenter types.Vec2u
+	workers        int
+	// set by Stop(), workers exit when they see it
+	stopped bool
 
 	implementation generatorImplementation
+	// called by Stop(), to stop the background work of the implementation, if it has any
+	stopImplementation func()
 }
 
 func newGenerator(implementation generatorImplementation) *Generator {
-	return &Generator{
-		requestsPool: make(map[types.Vec2u]types.Chunk),
-		// Use buffering for channels to be able to hold more than 1 request in a queue
-		requests:  make(chan types.Vec2u, 1024),
-		generated: make(chan types.Chunk, 1024),
+	workers := config.WorldgenWorkers
+	if workers <= 0 {
+		// leave one core for the game itself
+		workers = util.Clamp(runtime.NumCPU()-1, 1, runtime.NumCPU())
+	}
+
+	generator := &Generator{
+		pending:    make(map[types.Vec2u]types.Chunk),
+		inProgress: make(map[types.Vec2u]bool),
+		workers:    workers,
 
 		implementation: implementation,
 	}
+	generator.newRequest = sync.NewCond(&generator.mutex)
+	return generator
 }
 
+// Starts generation workers in the background
 func (generator *Generator) Run() {
+	for i := 0; i < generator.workers; i++ {
+		go generator.runWorker()
+	}
+}
+
+// Stops the workers, and drops all pending requests.
+// A worker that is generating a chunk right now exits after it is done.
+// Generation requests made after that are ignored.
+func (generator *Generator) Stop() {
+	generator.mutex.Lock()
+	generator.stopped = true
+	generator.pending = make(map[types.Vec2u]types.Chunk)
+	generator.newRequest.Broadcast()
+	generator.mutex.Unlock()
+
+	if generator.stopImplementation != nil {
+		generator.stopImplementation()
+	}
+}
+
+func (generator *Generator) runWorker() {
 	for {
-		request := <-generator.requests
+		generator.mutex.Lock()
+		for len(generator.pending) == 0 && !generator.stopped {
+			generator.newRequest.Wait()
+		}
+		if generator.stopped {
+			generator.mutex.Unlock()
+			return
+		}
+		coords, chunk := generator.nextRequest()
+		delete(generator.pending, coords)
+		generator.inProgress[coords] = false
+		generator.mutex.Unlock()
 
-		chunk := generator.requestsPool[request]
 		generator.implementation.generate(chunk)
 
-		generator.generated <- chunk
+		generator.mutex.Lock()
+		if cancelled := generator.inProgress[coords]; !cancelled {
+			generator.generated = append(generator.generated, chunk)
+		}
+		delete(generator.inProgress, coords)
+		generator.mutex.Unlock()
 	}
 }
 
+// Returns the pending request closest to the priority center.
+// Must be called with the mutex locked
+func (generator *Generator) nextRequest() (types.Vec2u, types.Chunk) {
+	var (
+		closest      types.Vec2u
+		closestChunk types.Chunk
+		bestDistance = int64(-1)
+	)
+	for coords, chunk := range generator.pending {
+		dx := int64(coords.X) - int64(generator.priorityCenter.X)
+		dy := int64(coords.Y) - int64(generator.priorityCenter.Y)
+		if distance := dx*dx + dy*dy; bestDistance == -1 || distance < bestDistance {
+			closest, closestChunk, bestDistance = coords, chunk, distance
+		}
+	}
+	return closest, closestChunk
+}
+
 // Requests a chunk generation
 // Generated chunks can be received through generator.Receive()
 func (generator *Generator) Generate(chunk types.Chunk) {
 	coords := chunk.Coords()
-	if _, exists := generator.requestsPool[coords]; exists {
+
+	generator.mutex.Lock()
+	defer generator.mutex.Unlock()
+
+	if generator.stopped {
+		return
+	}
+	if _, exists := generator.pending[coords]; exists {
+		return
+	}
+	if _, exists := generator.inProgress[coords]; exists {
+		// the chunk may have been cancelled, but now it is needed again
+		generator.inProgress[coords] = false
 		return
 	}
-	generator.requests <- coords
-	generator.requestsPool[coords] = chunk
+
+	generator.pending[coords] = chunk
+	generator.newRequest.Signal()
+}
+
+// Cancels the generation request of the chunk, if there is one.
+// If the chunk is being generated right now, it won't be returned from Receive()
+func (generator *Generator) Cancel(cx, cy uint64) {
+	coords := types.Vec2u{X: cx, Y: cy}
+
+	generator.mutex.Lock()
+	defer generator.mutex.Unlock()
+
+	delete(generator.pending, coords)
+	if _, exists := generator.inProgress[coords]; exists {
+		generator.inProgress[coords] = true
+	}
+}
+
+// Requests closest to the given point (in chunk coordinates) are generated first
+func (generator *Generator) SetPriorityCenter(center types.Vec2u) {
+	generator.mutex.Lock()
+	generator.priorityCenter = center
+	generator.mutex.Unlock()
 }
 
 func (generator *Generator) GenerateImmediately(chunk types.Chunk) {
@@ -124,24 +283,21 @@ func (generator *Generator) GenerateDummy(chunk types.Chunk) {
 
 // Returns a list of generated chunks
 func (generator *Generator) Receive() (chunks []types.Chunk) {
-	noMoreValues := false
-	for {
-		select {
-		case receivedChunk := <-generator.generated:
-			chunks = append(chunks, receivedChunk)
-			// remove chunk from request pool
-			delete(generator.requestsPool, receivedChunk.Coords())
-		default:
-			noMoreValues = true
-		}
+	generator.mutex.Lock()
+	defer generator.mutex.Unlock()
 
-		if noMoreValues {
-			break
-		}
-	}
+	chunks = generator.generated
+	generator.generated = nil
 	return
 }
 
 func (generator *Generator) Seed() int64 {
 	return generator.implementation.seed()
 }
+
+func (generator *Generator) BiomeAt(bx, by uint64) string {
+	if implementation, ok := generator.implementation.(biomeImplementation); ok {
+		return implementation.biomeAt(bx, by).String()
+	}
+	return ""
+}
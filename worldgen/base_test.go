@@ -0,0 +1,174 @@
+package worldgen
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/3elDU/bamboo/types"
+)
+
+// Generator only needs chunk coordinates, so the rest of the interface is left unimplemented
+type testChunk struct {
+	types.Chunk
+	coords types.Vec2u
+}
+
+func (c *testChunk) Coords() types.Vec2u {
+	return c.coords
+}
+
+type testImplementation struct{}
+
+func (testImplementation) generate(chunk types.Chunk) {
+	// pretend to do some work, so requests pile up
+	time.Sleep(10 * time.Microsecond)
+}
+func (testImplementation) generateDummy(chunk types.Chunk) {}
+func (testImplementation) seed() int64                     { return 0 }
+
+func newTestGenerator(workers int) *Generator {
+	generator := newGenerator(testImplementation{})
+	generator.workers = workers
+	return generator
+}
+
+// Waits until the generator returns the given number of chunks, failing the test after a timeout
+func receiveN(t *testing.T, generator *Generator, n int) []types.Chunk {
+	t.Helper()
+
+	received := make([]types.Chunk, 0, n)
+	deadline := time.Now().Add(10 * time.Second)
+	for len(received) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("received %v chunks out of %v", len(received), n)
+		}
+		received = append(received, generator.Receive()...)
+		time.Sleep(time.Millisecond)
+	}
+	return received
+}
+
+// Waits until the number of goroutines drops to the given number, failing the test after a timeout
+func waitForGoroutines(t *testing.T, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%v goroutines are still running, expected %v", runtime.NumGoroutine(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGeneratorConcurrentRequests(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	generator := newTestGenerator(4)
+	generator.Run()
+
+	const size = 32
+	var wg sync.WaitGroup
+	// several goroutines request the same chunks, so duplicates have to be filtered out
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for x := uint64(0); x < size; x++ {
+				for y := uint64(0); y < size; y++ {
+					generator.Generate(&testChunk{coords: types.Vec2u{X: x, Y: y}})
+				}
+				generator.SetPriorityCenter(types.Vec2u{X: x, Y: uint64(i)})
+			}
+		}(i)
+	}
+
+	// receive concurrently with the requests
+	seen := make(map[types.Vec2u]bool)
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	deadline := time.Now().Add(10 * time.Second)
+	for len(seen) < size*size {
+		if time.Now().After(deadline) {
+			t.Fatalf("received %v unique chunks out of %v", len(seen), size*size)
+		}
+		for _, chunk := range generator.Receive() {
+			seen[chunk.Coords()] = true
+		}
+	}
+	<-done
+
+	generator.Stop()
+	waitForGoroutines(t, goroutines)
+}
+
+func TestGeneratorStop(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	generator := newTestGenerator(4)
+	generator.Run()
+
+	// stop while the workers are busy, and while more requests are coming
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for x := uint64(0); x < 1000; x++ {
+			generator.Generate(&testChunk{coords: types.Vec2u{X: x}})
+		}
+	}()
+	time.Sleep(time.Millisecond)
+	generator.Stop()
+	wg.Wait()
+	waitForGoroutines(t, goroutines)
+
+	// requests after Stop() are ignored
+	generator.Receive()
+	generator.Generate(&testChunk{coords: types.Vec2u{X: 1000}})
+	time.Sleep(10 * time.Millisecond)
+	if chunks := generator.Receive(); len(chunks) != 0 {
+		t.Errorf("received %v chunks after the generator was stopped", len(chunks))
+	}
+}
+
+func TestGeneratorCancel(t *testing.T) {
+	// requests are queued before the workers are started, so nothing is picked up early
+	generator := newTestGenerator(2)
+	for x := uint64(0); x < 10; x++ {
+		generator.Generate(&testChunk{coords: types.Vec2u{X: x}})
+	}
+	for x := uint64(0); x < 10; x += 2 {
+		generator.Cancel(x, 0)
+	}
+	generator.Run()
+
+	received := receiveN(t, generator, 5)
+	for _, chunk := range received {
+		if chunk.Coords().X%2 == 0 {
+			t.Errorf("cancelled chunk %v was generated", chunk.Coords())
+		}
+	}
+
+	// nothing else should arrive
+	time.Sleep(50 * time.Millisecond)
+	if extra := generator.Receive(); len(extra) != 0 {
+		t.Errorf("received %v unexpected chunks", len(extra))
+	}
+}
+
+func TestGeneratorPriority(t *testing.T) {
+	generator := newTestGenerator(1)
+	for x := uint64(0); x < 20; x++ {
+		generator.Generate(&testChunk{coords: types.Vec2u{X: x}})
+	}
+	generator.SetPriorityCenter(types.Vec2u{X: 15})
+	generator.Run()
+
+	received := receiveN(t, generator, 20)
+	if first := received[0].Coords(); first.X != 15 {
+		t.Errorf("expected chunk closest to the priority center to be generated first, got %v", first)
+	}
+}
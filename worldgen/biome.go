@@ -0,0 +1,170 @@
+package worldgen
+
+import (
+	"github.com/3elDU/bamboo/types"
+)
+
+type Biome int
+
+const (
+	OceanBiome Biome = iota
+	BeachBiome
+	MeadowBiome
+	PineForestBiome
+	SnowyTundraBiome
+	SwampBiome
+	DesertBiome
+	RiverBiome
+	LakeBiome
+)
+
+// Biome selection constants.
+// Temperature and moisture change much slower than the terrain, so biomes span several islands,
+// their scale is set by the preset
+const (
+	// Temperature, below which snowy tundra will generate
+	ColdTemperature = 0.8
+	// Temperature, above which desert will generate, if it's dry enough
+	HotTemperature = 1.15
+	// Moisture, below which desert will generate, if it's hot enough
+	DryMoisture = 1.0
+	// Moisture, above which pine forest will generate
+	HumidMoisture = 0.95
+	// Moisture, above which swamp will generate
+	WetMoisture = 1.15
+)
+
+// Describes what the biome consists of
+type biomeInfo struct {
+	name string
+
+	// Base block of the biome
+	ground func() types.Block
+
+	// Uses secondary height.
+	// Height, below which the ground is replaced with water (pools in swamps)
+	poolHeight float64
+	// Height, below which the ground is left empty
+	emptyHeight float64
+	// Height, below which foliage will generate. Trees generate above it
+	foliageHeight float64
+
+	// Chances of the foliage are in the preset, see types.FoliageChances.
+	// Generated in the foliage zone, when nothing else was chosen. nil leaves the ground empty
+	foliage func() types.Block
+
+	// nil means that the biome has no trees
+	tree func() types.Block
+
+	// %Chance of sand being generated with stones or clay on it
+	sandParticlesChance float64
+}
+
+var biomes = [...]biomeInfo{
+	OceanBiome: {
+		name:   "ocean",
+		ground: func() types.Block { return types.NewBlock(types.WaterBlock) },
+	},
+	BeachBiome: {
+		name:                "beach",
+		ground:              func() types.Block { return types.NewBlock(types.SandBlock) },
+		sandParticlesChance: 0.03,
+	},
+	MeadowBiome: {
+		name:          "meadow",
+		ground:        func() types.Block { return types.NewBlock(types.GrassBlock) },
+		emptyHeight:   0.9,
+		foliageHeight: 1.3,
+		foliage:       func() types.Block { return types.NewBlock(types.ShortGrassBlock) },
+		tree:          func() types.Block { return types.NewBlock(types.PineTreeBlock) },
+	},
+	PineForestBiome: {
+		name:          "pine forest",
+		ground:        func() types.Block { return types.NewBlock(types.GrassBlock) },
+		emptyHeight:   0.8,
+		foliageHeight: 1.05,
+		foliage:       func() types.Block { return types.NewBlock(types.ShortGrassBlock) },
+		tree:          func() types.Block { return types.NewBlock(types.PineTreeBlock) },
+	},
+	SnowyTundraBiome: {
+		name:          "snowy tundra",
+		ground:        func() types.Block { return types.NewBlock(types.SnowBlock) },
+		emptyHeight:   1.1,
+		foliageHeight: 1.4,
+		tree:          func() types.Block { return types.NewBlock(types.PineTreeBlock) },
+	},
+	SwampBiome: {
+		name:          "swamp",
+		ground:        func() types.Block { return types.NewBlock(types.GrassBlock) },
+		poolHeight:    0.85,
+		emptyHeight:   0.9,
+		foliageHeight: 1.35,
+		foliage:       func() types.Block { return types.NewBlock(types.ShortGrassBlock) },
+		tree:          func() types.Block { return types.NewBlock(types.PineTreeBlock) },
+	},
+	DesertBiome: {
+		name:                "desert",
+		ground:              func() types.Block { return types.NewBlock(types.SandBlock) },
+		sandParticlesChance: 0.06,
+	},
+	RiverBiome: {
+		name:   "river",
+		ground: func() types.Block { return types.NewBlock(types.WaterBlock) },
+	},
+	LakeBiome: {
+		name:   "lake",
+		ground: func() types.Block { return types.NewBlock(types.WaterBlock) },
+	},
+}
+
+func (b Biome) String() string {
+	if b < 0 || int(b) >= len(biomes) {
+		return "unknown"
+	}
+	return biomes[b].name
+}
+
+func (b Biome) info() *biomeInfo {
+	return &biomes[b]
+}
+
+// Returns the biome with that name, like "pine forest"
+func biomeByName(name string) (Biome, bool) {
+	for b := range biomes {
+		if biomes[b].name == name {
+			return Biome(b), true
+		}
+	}
+	return 0, false
+}
+
+// Picks the biome for the block, using base height, rivers and lakes, temperature and moisture
+func (generator *OverworldGenerator) biomeAt(x, y uint64) Biome {
+	baseHeight := generator.baseHeight(x, y)
+	if baseHeight <= generator.preset.WaterHeight {
+		return OceanBiome
+	}
+	// rivers flow through beaches, into the ocean
+	if water, exists := generator.hydrology.waterAt(x, y); exists {
+		return water
+	}
+	if baseHeight <= generator.preset.SandHeight {
+		return BeachBiome
+	}
+
+	temperature := height(generator.temperaturePerlin, x, y, generator.preset.BiomeScale)
+	moisture := height(generator.moisturePerlin, x, y, generator.preset.BiomeScale)
+
+	switch {
+	case temperature < ColdTemperature:
+		return SnowyTundraBiome
+	case temperature > HotTemperature && moisture < DryMoisture:
+		return DesertBiome
+	case moisture > WetMoisture:
+		return SwampBiome
+	case moisture > HumidMoisture:
+		return PineForestBiome
+	default:
+		return MeadowBiome
+	}
+}
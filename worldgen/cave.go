@@ -1,29 +1,142 @@
 package worldgen
 
 import (
+	"log"
+	"math"
+	"math/rand"
+
 	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/world_type"
 	"github.com/aquilax/go-perlin"
+	"github.com/google/uuid"
 )
 
 const (
 	// Chance that cave floor will have little sprouts growing on it
 	CaveFloorSproutsChance = 0.1
 
-	// %Chance that iron ore will be generated on this block
-	// (Iron ore can only generated on cave floor block, not inside walls)
-	IronOreChance = 0.006
+	// Ores generate in veins, that follow the ridges of their own noise.
+	// Scale of the vein noise, smaller values make veins shorter and more twisted
+	OreVeinScaleFactor = config.PerlinNoiseScaleFactor / 4
+
+	// %Chance of generating a descent to the next cave tier in a chunk
+	CaveDescentChance = 0.03
+)
+
+// Kinds of ore, each one has its own vein noise
+type ore int
+
+const (
+	coalOre ore = iota
+	copperOre
+	goldOre
+	ironOre
+	oreCount
 )
 
+var oreBlocks = [oreCount]func() types.Block{
+	coalOre:   func() types.Block { return types.NewBlock(types.CoalOreBlock) },
+	copperOre: func() types.Block { return types.NewBlock(types.CopperOreBlock) },
+	goldOre:   func() types.Block { return types.NewBlock(types.GoldOreBlock) },
+	ironOre:   func() types.Block { return types.NewBlock(types.IronOreBlock) },
+}
+
+// How an ore generates in a cave tier.
+// (Ore can only generated on cave floor block, not inside walls)
+type oreVein struct {
+	ore ore
+	// Vein noise is from 0 to 2, ore generates where it is closer than this to 1
+	thickness float64
+	// %Chance of an ore block inside the vein, veins aren't solid
+	density float64
+}
+
+// Parameters of a cave tier. The deeper the cave, the narrower the tunnels,
+// the more ores and the more hazards there are.
+type caveTier struct {
+	// Height, below which cave floor will generate, instead of a wall
+	floorHeight float64
+	// %Chance that cave floor will have little sprouts growing on it
+	sproutsChance float64
+	// If veins of different ores cross, the first one in the list wins
+	ores []oreVein
+
+	// Hazards use separate noise. 0 means that the hazard doesn't generate.
+	// Height, above which cave floor turns into a pit
+	pitHeight float64
+	// Height, below which cave floor is flooded with water
+	waterHeight float64
+
+	// %Chance of generating a descent to the deeper tier in a chunk.
+	// Deepest tier has no descents
+	descentChance float64
+}
+
+var caveTiers = map[world_type.WorldType]caveTier{
+	world_type.Cave: {
+		floorHeight:   1,
+		sproutsChance: CaveFloorSproutsChance,
+		ores: []oreVein{
+			{ore: coalOre, thickness: 0.02, density: 0.6},
+			{ore: ironOre, thickness: 0.004, density: 0.5},
+			{ore: goldOre, thickness: 0.012, density: 0.5},
+			{ore: copperOre, thickness: 0.006, density: 0.4},
+		},
+		descentChance: CaveDescentChance,
+	},
+	world_type.Cave2: {
+		floorHeight:   0.95,
+		sproutsChance: 0.04,
+		ores: []oreVein{
+			{ore: coalOre, thickness: 0.012, density: 0.5},
+			{ore: copperOre, thickness: 0.02, density: 0.6},
+			{ore: goldOre, thickness: 0.01, density: 0.4},
+			{ore: ironOre, thickness: 0.01, density: 0.4},
+		},
+		pitHeight:     1.45,
+		waterHeight:   0.55,
+		descentChance: CaveDescentChance,
+	},
+	world_type.Cave3: {
+		floorHeight: 0.9,
+		ores: []oreVein{
+			{ore: ironOre, thickness: 0.025, density: 0.6},
+			{ore: copperOre, thickness: 0.015, density: 0.5},
+			{ore: goldOre, thickness: 0.012, density: 0.5},
+		},
+		pitHeight:   1.35,
+		waterHeight: 0.6,
+	},
+}
+
 type CaveGenerator struct {
 	noiseSeed int64
+	worldType world_type.WorldType
+	tier      caveTier
 	noise     *perlin.Perlin
+	// used for hazards and descents
+	hazardNoise *perlin.Perlin
+	// one for each ore
+	oreNoise [oreCount]*perlin.Perlin
 }
 
-func NewCaveGenerator(seed int64) types.WorldGenerator {
+func NewCaveGenerator(seed int64, worldType world_type.WorldType) types.WorldGenerator {
+	tier, ok := caveTiers[worldType]
+	if !ok {
+		log.Panicf("no cave tier associated with world type %v", worldType)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
 	implementation := &CaveGenerator{
-		noiseSeed: seed,
-		noise:     perlin.NewPerlin(2, 2, 1, seed),
+		noiseSeed:   seed,
+		worldType:   worldType,
+		tier:        tier,
+		noise:       perlin.NewPerlin(2, 2, 1, seed),
+		hazardNoise: perlin.NewPerlin(2, 2, 4, rng.Int63()),
+	}
+	for i := range implementation.oreNoise {
+		implementation.oreNoise[i] = perlin.NewPerlin(2, 2, 3, rng.Int63())
 	}
 	return newGenerator(implementation)
 }
@@ -32,50 +145,94 @@ func (generator *CaveGenerator) generateBase(x, y uint64) types.Block {
 	h := height(generator.noise, x, y, config.PerlinNoiseScaleFactor/16)
 	features := makeFeatures(generator.noise, x, y)
 
-	if h < 1 {
+	if h < generator.tier.floorHeight {
 		return types.NewCaveFloorBlock(
-			features.f1 < CaveFloorSproutsChance,
+			features.f1 < generator.tier.sproutsChance,
 		)
 	} else {
-		return types.NewCaveWallBlock()
+		return types.NewBlock(types.CaveWallBlock)
 	}
 }
 
-// checks if block is neighboring with air (cave floor) block on all four sides
-func (generator *CaveGenerator) blockSurroundedByAir(x, y uint64) bool {
-	neighbors := []types.Vec2u{
-		{X: x, Y: y - 1}, // top
-		{X: x - 1, Y: y}, // left
-		{X: x + 1, Y: y}, // right
-		{X: x, Y: y + 1}, // bottom
+func (generator *CaveGenerator) generateOre(x, y uint64, previous types.Block) types.Block {
+	if previous.Type() != types.CaveFloorBlock {
+		return previous
 	}
 
-	for _, neighbor := range neighbors {
-		if generator.generateBase(neighbor.X, neighbor.Y).Type() != types.CaveFloorBlock {
-			return false
+	for _, vein := range generator.tier.ores {
+		noise := generator.oreNoise[vein.ore]
+		if math.Abs(height(noise, x, y, OreVeinScaleFactor)-1) >= vein.thickness {
+			continue
+		}
+		if makeFeatures(noise, x, y).f1 < vein.density {
+			return oreBlocks[vein.ore]()
 		}
 	}
-	return true
+	return previous
 }
 
-func (generator *CaveGenerator) generateOre(x, y uint64, previous types.Block) types.Block {
+// turns cave floor into pits and underground pools
+func (generator *CaveGenerator) generateHazards(x, y uint64, previous types.Block) types.Block {
 	if previous.Type() != types.CaveFloorBlock {
 		return previous
 	}
 
-	features := makeFeatures(generator.noise, x, y)
+	h := height(generator.hazardNoise, x, y, config.PerlinNoiseScaleFactor/8)
+	switch {
+	case generator.tier.pitHeight > 0 && h > generator.tier.pitHeight:
+		return types.NewBlock(types.PitBlock)
+	case h < generator.tier.waterHeight:
+		return types.NewBlock(types.WaterBlock)
+	}
+	return previous
+}
 
-	// if a block is surrounded by air, chances of generating ore blocks are 2 times lower
-	surroundedByAir := generator.blockSurroundedByAir(x, y)
-	var chanceDivider = 1.0
-	if surroundedByAir {
-		chanceDivider = 2.0
+// Checks that the block and all 8 of its neighbors are cave floor, without any hazards
+func (generator *CaveGenerator) safeFloorAround(x, y uint64) bool {
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			bx, by := uint64(int64(x)+int64(dx)), uint64(int64(y)+int64(dy))
+			block := generator.generateHazards(bx, by, generator.generateBase(bx, by))
+			if block.Type() != types.CaveFloorBlock {
+				return false
+			}
+		}
 	}
+	return true
+}
 
-	if features.f1 < IronOreChance/chanceDivider {
-		return types.NewIronOreBlock()
+func (generator *CaveGenerator) generateDescent(chunk types.Chunk) {
+	deeper, ok := generator.worldType.Deeper()
+	if !ok || generator.tier.descentChance == 0 {
+		return
+	}
+
+	chunkCoords := chunk.BlockCoords()
+	features := makeFeatures(generator.hazardNoise, chunkCoords.X, chunkCoords.Y)
+	if features.f1 >= generator.tier.descentChance {
+		return
+	}
+
+	// same as with cave entrances, use a bunch of hardcoded possible coordinates
+	possibleCoordinates := []types.Vec2u{
+		{X: 8, Y: 4},
+		{X: 4, Y: 12},
+		{X: 12, Y: 12},
+	}
+	for _, coords := range possibleCoordinates {
+		if !generator.safeFloorAround(chunkCoords.X+coords.X, chunkCoords.Y+coords.Y) {
+			continue
+		}
+
+		rng := rand.New(rand.NewSource(features.i1))
+		id, err := uuid.NewRandomFromReader(rng)
+		if err != nil {
+			log.Panicf("failed to generate UUID for cave: %v", err)
+		}
+		log.Printf("descent to %v at %v, %v: %v", deeper, chunkCoords.X+coords.X, chunkCoords.Y+coords.Y, id)
+		chunk.SetBlock(uint(coords.X), uint(coords.Y), types.NewCaveDescentBlock(id, deeper))
+		return
 	}
-	return previous
 }
 
 func (generator *CaveGenerator) generate(chunk types.Chunk) {
@@ -87,9 +244,12 @@ func (generator *CaveGenerator) generate(chunk types.Chunk) {
 
 			block := generator.generateBase(bx, by)
 			block = generator.generateOre(bx, by, block)
+			block = generator.generateHazards(bx, by, block)
 			chunk.SetBlock(x, y, block)
 		}
 	}
+
+	generator.generateDescent(chunk)
 }
 
 func (generator *CaveGenerator) generateDummy(chunk types.Chunk) {
@@ -0,0 +1,225 @@
+package worldgen
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	_ "github.com/3elDU/bamboo/blocks_impl"
+	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/world_type"
+)
+
+// When a change to the generator is intentional, regenerate the golden files with
+//
+//	go test ./worldgen -run Golden -update-golden
+//
+// and commit them together with the change
+var updateGolden = flag.Bool("update-golden", false, "regenerate worldgen golden files, instead of comparing against them")
+
+const goldenDirectory = "testdata/golden"
+
+// Generated square of chunks around the center of the world, in chunks
+const goldenArea = 8
+
+type goldenCase struct {
+	name     string
+	metadata types.Save
+}
+
+func overworldCase(name string, seed int64, shape types.WorldShape) goldenCase {
+	return goldenCase{name, types.Save{
+		Seed:      seed,
+		Size:      types.Vec2u{X: 1024, Y: 1024},
+		Shape:     shape,
+		WorldType: world_type.Overworld,
+	}}
+}
+
+func caveCase(name string, seed int64, worldType world_type.WorldType, size uint64) goldenCase {
+	return goldenCase{name, types.Save{
+		Seed:      seed,
+		Size:      types.Vec2u{X: size, Y: size},
+		WorldType: worldType,
+	}}
+}
+
+// Sizes are hardcoded, so the goldens don't change with the config
+var goldenCases = []goldenCase{
+	overworldCase("overworld_1", 1, types.SingleIslandShape),
+	overworldCase("overworld_42", 42, types.SingleIslandShape),
+	overworldCase("overworld_archipelago_7", 7, types.ArchipelagoShape),
+	overworldCase("overworld_continent_7", 7, types.ContinentShape),
+	caveCase("cave_1", 1, world_type.Cave, 256),
+	caveCase("cave2_1", 1, world_type.Cave2, 192),
+	caveCase("cave3_1", 1, world_type.Cave3, 128),
+}
+
+// Only records block types, that's all the golden files need
+type goldenChunk struct {
+	types.Chunk
+	coords types.Vec2u
+	blocks [16][16]types.BlockType
+}
+
+func (c *goldenChunk) Coords() types.Vec2u {
+	return c.coords
+}
+func (c *goldenChunk) BlockCoords() types.Vec2u {
+	return types.Vec2u{X: c.coords.X * 16, Y: c.coords.Y * 16}
+}
+func (c *goldenChunk) SetBlock(x, y uint, block types.Block) {
+	c.blocks[x][y] = block.Type()
+}
+
+func (c *goldenChunk) hash() uint64 {
+	hasher := fnv.New64a()
+	for x := range c.blocks {
+		for y := range c.blocks[x] {
+			blockType := c.blocks[x][y]
+			hasher.Write([]byte{byte(blockType), byte(blockType >> 8)})
+		}
+	}
+	return hasher.Sum64()
+}
+
+// Generates chunks around the center of the world, and returns their hashes.
+// Chunks are generated in the given order, to check that the order doesn't matter
+func generateGolden(metadata types.Save, reverse bool) map[types.Vec2u]uint64 {
+	generator := NewWorldgenForWorld(metadata)
+
+	center := types.Vec2u{X: metadata.Size.X / 16 / 2, Y: metadata.Size.Y / 16 / 2}
+	coords := make([]types.Vec2u, 0, goldenArea*goldenArea)
+	for cx := center.X - goldenArea/2; cx < center.X+goldenArea/2; cx++ {
+		for cy := center.Y - goldenArea/2; cy < center.Y+goldenArea/2; cy++ {
+			coords = append(coords, types.Vec2u{X: cx, Y: cy})
+		}
+	}
+	if reverse {
+		for i, j := 0, len(coords)-1; i < j; i, j = i+1, j-1 {
+			coords[i], coords[j] = coords[j], coords[i]
+		}
+	}
+
+	hashes := make(map[types.Vec2u]uint64)
+	for _, c := range coords {
+		chunk := &goldenChunk{coords: c}
+		generator.GenerateImmediately(chunk)
+		hashes[c] = chunk.hash()
+	}
+	return hashes
+}
+
+func goldenPath(name string) string {
+	return filepath.Join(goldenDirectory, name+".txt")
+}
+
+func writeGolden(path string, hashes map[types.Vec2u]uint64) error {
+	coords := make([]types.Vec2u, 0, len(hashes))
+	for c := range hashes {
+		coords = append(coords, c)
+	}
+	sort.Slice(coords, func(i, j int) bool {
+		if coords[i].X != coords[j].X {
+			return coords[i].X < coords[j].X
+		}
+		return coords[i].Y < coords[j].Y
+	})
+
+	var b strings.Builder
+	b.WriteString("# worldgen golden file, regenerate with: go test ./worldgen -run Golden -update-golden\n")
+	b.WriteString("# chunk x, chunk y, hash of block types\n")
+	for _, c := range coords {
+		fmt.Fprintf(&b, "%v %v %016x\n", c.X, c.Y, hashes[c])
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+func readGolden(path string) (map[types.Vec2u]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	hashes := make(map[types.Vec2u]uint64)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		var c types.Vec2u
+		var hash uint64
+		if _, err := fmt.Sscanf(text, "%d %d %x", &c.X, &c.Y, &hash); err != nil {
+			return nil, fmt.Errorf("%v:%v: %v", path, line, err)
+		}
+		hashes[c] = hash
+	}
+	return hashes, scanner.Err()
+}
+
+func TestGolden(t *testing.T) {
+	for _, tc := range goldenCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			generated := generateGolden(tc.metadata, false)
+			path := goldenPath(tc.name)
+
+			if *updateGolden {
+				if err := writeGolden(path, generated); err != nil {
+					t.Fatalf("failed to write golden file: %v", err)
+				}
+				return
+			}
+
+			golden, err := readGolden(path)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run with -update-golden to create it): %v", err)
+			}
+
+			mismatched := make([]string, 0)
+			for c, hash := range generated {
+				if expected, exists := golden[c]; !exists || expected != hash {
+					mismatched = append(mismatched, fmt.Sprintf("%v,%v", c.X, c.Y))
+				}
+			}
+			if len(golden) != len(generated) {
+				t.Errorf("golden file has %v chunks, generated %v", len(golden), len(generated))
+			}
+			if len(mismatched) > 0 {
+				sort.Strings(mismatched)
+				t.Errorf("%v of %v chunks differ from the golden file: %v\n"+
+					"if the change is intentional, run with -update-golden",
+					len(mismatched), len(generated), strings.Join(mismatched, " "))
+			}
+		})
+	}
+}
+
+// Chunks are generated by workers in any order, the result must be the same
+func TestGoldenOrderIndependent(t *testing.T) {
+	for _, tc := range goldenCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			forward := generateGolden(tc.metadata, false)
+			backward := generateGolden(tc.metadata, true)
+			for c, hash := range forward {
+				if backward[c] != hash {
+					t.Errorf("chunk %v,%v differs, when chunks are generated in reverse order", c.X, c.Y)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package worldgen
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"math/rand"
+
+	"github.com/3elDU/bamboo/types"
+)
+
+// Hydrology constants
+const (
+	// Rivers are traced on a coarse grid of cells, this is the size of a cell in blocks
+	HydrologyCellSize = 4
+	// The world is split into square regions of this size (in blocks), each region can have one river source
+	RiverSourceSpacing = 64
+	// Rivers get wider the further they flow, from minimum to maximum width (in blocks)
+	RiverMinWidth = 1.5
+	RiverMaxWidth = 5
+	// How many cells it takes for the river to reach maximum width
+	RiverWideningLength = 120
+	// Lakes bigger than this (in cells) are not filled further, and the river ends there
+	MaxLakeCells = 150
+)
+
+// Rivers and lakes are traced through the whole world at once,
+// so chunks that are generated independently (in any order, on different workers) still line up at their borders.
+// Tracing runs in the background, after the generator is created, and waterAt() waits for it to finish.
+// If the context is cancelled, tracing stops early, and the world is left with the rivers traced so far.
+// After that it is only read from, so it is safe to use from multiple goroutines.
+type hydrology struct {
+	size types.Vec2u
+	// base height at the center of each cell
+	heights [][]float64
+	// Water of each chunk, column by column. nil for chunks without any water
+	water []*chunkWater
+	// closed, when tracing is finished
+	done chan struct{}
+}
+
+// Kind of water on each block of the chunk, indexed by x*16+y
+type chunkWater [16 * 16]waterKind
+
+type waterKind uint8
+
+const (
+	noWater waterKind = iota
+	riverWater
+	lakeWater
+)
+
+type cell struct {
+	x, y int
+}
+
+func newHydrology(ctx context.Context, generator *OverworldGenerator, seed int64) *hydrology {
+	size := generator.metadata.Size
+	h := &hydrology{
+		size:    size,
+		heights: make([][]float64, size.X/HydrologyCellSize),
+		water:   make([]*chunkWater, (size.X/16)*(size.Y/16)),
+		done:    make(chan struct{}),
+	}
+	go h.trace(ctx, generator, seed)
+	return h
+}
+
+func (h *hydrology) trace(ctx context.Context, generator *OverworldGenerator, seed int64) {
+	defer close(h.done)
+
+	for x := range h.heights {
+		if ctx.Err() != nil {
+			return
+		}
+		h.heights[x] = make([]float64, h.size.Y/HydrologyCellSize)
+		for y := range h.heights[x] {
+			h.heights[x][y] = generator.baseHeight(
+				uint64(x)*HydrologyCellSize+HydrologyCellSize/2,
+				uint64(y)*HydrologyCellSize+HydrologyCellSize/2,
+			)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	// cells, that are already occupied by rivers and lakes, so other rivers can flow into them
+	occupied := make(map[cell]bool)
+
+	regionCells := RiverSourceSpacing / HydrologyCellSize
+	for rx := 0; rx < len(h.heights); rx += regionCells {
+		for ry := 0; ry < len(h.heights[rx]); ry += regionCells {
+			if ctx.Err() != nil {
+				return
+			}
+			// rng is used for every region, even if there is no river in it,
+			// so the rivers don't shift if a region changes
+			chance := rng.Float64()
+			riverSeed := rng.Int63()
+
+			source, found := h.highestCell(generator, rx, ry, regionCells)
+			if !found || chance > generator.preset.RiverChance || occupied[source] {
+				continue
+			}
+			h.traceRiver(generator, source, occupied, rand.New(rand.NewSource(riverSeed)))
+		}
+	}
+}
+
+func (h *hydrology) inBounds(c cell) bool {
+	return c.x >= 0 && c.y >= 0 && c.x < len(h.heights) && c.y < len(h.heights[c.x])
+}
+
+func (h *hydrology) height(c cell) float64 {
+	return h.heights[c.x][c.y]
+}
+
+func (h *hydrology) neighbors(c cell) []cell {
+	neighbors := make([]cell, 0, 8)
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			n := cell{c.x + dx, c.y + dy}
+			if (dx != 0 || dy != 0) && h.inBounds(n) {
+				neighbors = append(neighbors, n)
+			}
+		}
+	}
+	return neighbors
+}
+
+// Returns the highest cell in the region, if it is high enough for a river source
+func (h *hydrology) highestCell(generator *OverworldGenerator, rx, ry, regionCells int) (cell, bool) {
+	highest := cell{rx, ry}
+	for x := rx; x < rx+regionCells && x < len(h.heights); x++ {
+		for y := ry; y < ry+regionCells && y < len(h.heights[x]); y++ {
+			if h.heights[x][y] > h.height(highest) {
+				highest = cell{x, y}
+			}
+		}
+	}
+	return highest, h.height(highest) > generator.preset.RiverSourceHeight
+}
+
+// Follows the slope downhill from the source, until the river reaches the ocean or another river.
+// When the river gets stuck in a basin, the basin is filled with a lake, and the river continues from its outlet.
+func (h *hydrology) traceRiver(generator *OverworldGenerator, source cell, occupied map[cell]bool, rng *rand.Rand) {
+	path := []cell{source}
+	// cells of this river and its lakes, the river must not flow back into them
+	visited := map[cell]bool{source: true}
+
+	for current := source; h.height(current) > generator.preset.WaterHeight; {
+		next, found := current, false
+		for _, n := range h.neighbors(current) {
+			if !visited[n] && h.height(n) < h.height(next) {
+				next, found = n, true
+			}
+		}
+
+		if !found {
+			lake, level, outlet, overflows := h.fillBasin(current, visited)
+			h.placeLake(generator, lake, level)
+			for _, c := range lake {
+				visited[c] = true
+				occupied[c] = true
+			}
+			if !overflows {
+				break
+			}
+			next = outlet
+		}
+
+		path = append(path, next)
+		visited[next] = true
+		if occupied[next] {
+			// flows into another river or lake
+			break
+		}
+		current = next
+	}
+
+	for _, c := range path {
+		occupied[c] = true
+	}
+	h.placeRiver(path, rng)
+}
+
+// Priority flood: the lake grows over its lowest neighboring cell, until water finds a way out.
+// Returns cells of the lake, its water level, and the cell, where water flows out of the lake.
+// If the lake gets too big, overflows is false, and the river ends in the lake.
+func (h *hydrology) fillBasin(bottom cell, visited map[cell]bool) (lake []cell, level float64, outlet cell, overflows bool) {
+	lake = []cell{bottom}
+	level = h.height(bottom)
+
+	queued := map[cell]bool{bottom: true}
+	shore := &cellHeap{heights: h}
+	push := func(c cell) {
+		for _, n := range h.neighbors(c) {
+			if !queued[n] && !visited[n] {
+				queued[n] = true
+				heap.Push(shore, n)
+			}
+		}
+	}
+	push(bottom)
+
+	for shore.Len() > 0 {
+		lowest := heap.Pop(shore).(cell)
+		if h.height(lowest) < level {
+			return lake, level, lowest, true
+		}
+		if len(lake) >= MaxLakeCells {
+			break
+		}
+
+		lake = append(lake, lowest)
+		level = h.height(lowest)
+		push(lowest)
+	}
+
+	return lake, level, cell{}, false
+}
+
+// Blocks in and around lake cells, that are below the water level, are covered by the lake.
+// This gives lakes a natural shoreline, instead of a blocky one.
+func (h *hydrology) placeLake(generator *OverworldGenerator, lake []cell, level float64) {
+	checked := make(map[types.Vec2u]bool)
+	for _, c := range lake {
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				for bx := 0; bx < HydrologyCellSize; bx++ {
+					for by := 0; by < HydrologyCellSize; by++ {
+						x := (c.x+dx)*HydrologyCellSize + bx
+						y := (c.y+dy)*HydrologyCellSize + by
+						if x < 0 || y < 0 || uint64(x) >= h.size.X || uint64(y) >= h.size.Y {
+							continue
+						}
+
+						block := types.Vec2u{X: uint64(x), Y: uint64(y)}
+						if checked[block] {
+							continue
+						}
+						checked[block] = true
+
+						if generator.baseHeight(block.X, block.Y) <= level {
+							h.setWater(block.X, block.Y, lakeWater)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// the bottom of the lake is always covered
+	bottom := types.Vec2u{
+		X: uint64(lake[0].x)*HydrologyCellSize + HydrologyCellSize/2,
+		Y: uint64(lake[0].y)*HydrologyCellSize + HydrologyCellSize/2,
+	}
+	h.setWater(bottom.X, bottom.Y, lakeWater)
+}
+
+// Draws the river along the path, as a line of circles
+func (h *hydrology) placeRiver(path []cell, rng *rand.Rand) {
+	// shift points of the path randomly, so rivers don't follow the grid
+	points := make([]types.Vec2f, len(path))
+	for i, c := range path {
+		points[i] = types.Vec2f{
+			X: (float64(c.x) + 0.5 + rng.Float64() - 0.5) * HydrologyCellSize,
+			Y: (float64(c.y) + 0.5 + rng.Float64() - 0.5) * HydrologyCellSize,
+		}
+	}
+
+	for i := 1; i < len(points); i++ {
+		width := RiverMinWidth + (RiverMaxWidth-RiverMinWidth)*math.Min(float64(i)/RiverWideningLength, 1)
+		radius := width / 2
+
+		from, to := points[i-1], points[i]
+		length := math.Hypot(to.X-from.X, to.Y-from.Y)
+		for t := 0.0; t <= length; t += 0.5 {
+			h.placeCircle(from.X+(to.X-from.X)*t/length, from.Y+(to.Y-from.Y)*t/length, radius)
+		}
+	}
+}
+
+func (h *hydrology) placeCircle(cx, cy, radius float64) {
+	for x := math.Floor(cx - radius); x <= cx+radius; x++ {
+		for y := math.Floor(cy - radius); y <= cy+radius; y++ {
+			if x < 0 || y < 0 || x >= float64(h.size.X) || y >= float64(h.size.Y) {
+				continue
+			}
+			// distance from the center of the block
+			if math.Hypot(x+0.5-cx, y+0.5-cy) > radius {
+				continue
+			}
+
+			// lakes take precedence, so the lake doesn't turn into a river, where the river flows through it
+			if h.kindAt(uint64(x), uint64(y)) != lakeWater {
+				h.setWater(uint64(x), uint64(y), riverWater)
+			}
+		}
+	}
+}
+
+// Index of the chunk in h.water
+func (h *hydrology) chunkIndex(x, y uint64) uint64 {
+	return (x/16)*(h.size.Y/16) + y/16
+}
+
+func (h *hydrology) kindAt(x, y uint64) waterKind {
+	chunk := h.water[h.chunkIndex(x, y)]
+	if chunk == nil {
+		return noWater
+	}
+	return chunk[(x%16)*16+y%16]
+}
+
+func (h *hydrology) setWater(x, y uint64, kind waterKind) {
+	i := h.chunkIndex(x, y)
+	if h.water[i] == nil {
+		h.water[i] = new(chunkWater)
+	}
+	h.water[i][(x%16)*16+y%16] = kind
+}
+
+// Returns RiverBiome or LakeBiome, if the block is covered by water.
+// Blocks until the rivers are traced.
+func (h *hydrology) waterAt(x, y uint64) (Biome, bool) {
+	<-h.done
+
+	if x >= h.size.X || y >= h.size.Y {
+		return 0, false
+	}
+	switch h.kindAt(x, y) {
+	case riverWater:
+		return RiverBiome, true
+	case lakeWater:
+		return LakeBiome, true
+	}
+	return 0, false
+}
+
+// Min-heap of cells by their height
+type cellHeap struct {
+	cells   []cell
+	heights *hydrology
+}
+
+func (c *cellHeap) Len() int { return len(c.cells) }
+func (c *cellHeap) Less(i, j int) bool {
+	return c.heights.height(c.cells[i]) < c.heights.height(c.cells[j])
+}
+func (c *cellHeap) Swap(i, j int)      { c.cells[i], c.cells[j] = c.cells[j], c.cells[i] }
+func (c *cellHeap) Push(x interface{}) { c.cells = append(c.cells, x.(cell)) }
+func (c *cellHeap) Pop() interface{} {
+	last := c.cells[len(c.cells)-1]
+	c.cells = c.cells[:len(c.cells)-1]
+	return last
+}
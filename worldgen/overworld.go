@@ -4,83 +4,109 @@ Functions related to world generation
 package worldgen
 
 import (
-	"log"
+	"context"
 	"math/rand"
+	"sync"
 
-	"github.com/google/uuid"
-
-	"github.com/3elDU/bamboo/config"
 	"github.com/3elDU/bamboo/types"
 	"github.com/aquilax/go-perlin"
 )
 
-// Chances of generating certain structures, blocks, and other worldgen-related constants
+// Default chances of generating structures, they can be changed by the preset.
+// Terrain parameters are in the preset, see preset.go. Foliage and trees depend on the biome, see biome.go
 const (
-	// Uses base height.
-	// Height, below which water will generate
-	WaterHeight = 1.0
-	// Height, below which sand will generate
-	SandHeight = 1.1
-
-	// Uses secondary height.
-	// Height, below which grass will generate
-	GrassHeight = 0.9
-	// Height, below which foliage will generate
-	FoliageHeight = 1.3
-
-	// %Chance of on sand being generated wuth stones or clay on it
-	SandWithParticlesChance = 0.03
-
-	// %Chance of generating a berry bush
-	BerryBushChance = 0.005
-	// %Chance of generating a mushroom
-	MushroomChance = 0.015
-	// %Chance of generating a flower
-	FlowerChance = 0.06
 	// %Chance of generating cave entrance in a chunk
 	CaveEntranceChance = 0.05
+	// %Chances of generating other structures in their regions, see structures.go
+	RuinsChance       = 0.4
+	CampChance        = 0.3
+	StoneCircleChance = 0.25
 )
 
 type OverworldGenerator struct {
 	metadata types.Save
+	preset   types.WorldgenPreset
 	// Separate perlin noise generators for base blocks and vegetation/features
 	basePerlin      *perlin.Perlin
 	secondaryPerlin *perlin.Perlin
+	// Used for picking biomes
+	temperaturePerlin *perlin.Perlin
+	moisturePerlin    *perlin.Perlin
+	// Rivers and lakes
+	hydrology *hydrology
+
+	structureSeed int64
+	// guards placements
+	structuresMutex sync.Mutex
+	// where structures spawned, by region
+	placements map[placementKey]structurePlacement
 }
 
 func NewOverworldGenerator(metadata types.Save) types.WorldGenerator {
+	// tracing the rivers is stopped together with the generator
+	ctx, cancel := context.WithCancel(context.Background())
+	generator := newGenerator(newOverworldGenerator(ctx, metadata))
+	generator.stopImplementation = cancel
+	return generator
+}
+
+// Rivers are traced in the background, until they are finished, or ctx is cancelled
+func newOverworldGenerator(ctx context.Context, metadata types.Save) *OverworldGenerator {
 	// make a random generator using global world seed
 	globalSeed := rand.New(rand.NewSource(metadata.Seed))
 
 	// generate perlin noise seeds, using it
+	// (new seeds go last, so the base height of older worlds stays the same)
 	var (
-		baseSeed      = globalSeed.Int63()
-		secondarySeed = globalSeed.Int63()
+		baseSeed        = globalSeed.Int63()
+		secondarySeed   = globalSeed.Int63()
+		temperatureSeed = globalSeed.Int63()
+		moistureSeed    = globalSeed.Int63()
+		hydrologySeed   = globalSeed.Int63()
+		structureSeed   = globalSeed.Int63()
 	)
 
+	preset := PresetFor(metadata)
+	newPerlin := func(seed int64) *perlin.Perlin {
+		return perlin.NewPerlin(preset.NoiseAlpha, preset.NoiseBeta, preset.NoiseOctaves, seed)
+	}
+
 	implementation := &OverworldGenerator{
-		metadata:        metadata,
-		basePerlin:      perlin.NewPerlin(2, 2, 16, baseSeed),
-		secondaryPerlin: perlin.NewPerlin(2, 2, 16, secondarySeed),
+		metadata:          metadata,
+		preset:            preset,
+		basePerlin:        newPerlin(baseSeed),
+		secondaryPerlin:   newPerlin(secondarySeed),
+		temperaturePerlin: newPerlin(temperatureSeed),
+		moisturePerlin:    newPerlin(moistureSeed),
+		structureSeed:     structureSeed,
+		placements:        make(map[placementKey]structurePlacement),
 	}
+	// rivers are traced using base height, so the generator has to be set up first
+	implementation.hydrology = newHydrology(ctx, implementation, hydrologySeed)
 
-	return newGenerator(implementation)
+	return implementation
+}
+
+func (generator *OverworldGenerator) baseHeight(x, y uint64) float64 {
+	preset := &generator.preset
+	// same as height(), but the noise is scaled before it is shifted to 0..2
+	noise := generator.basePerlin.Noise2D(float64(x)/preset.NoiseScale, float64(y)/preset.NoiseScale)
+	return applyMask(generator.metadata.Size, generator.metadata.Shape, preset.MaskRadius, preset.MaskFalloff, float64(x), float64(y),
+		noise*preset.HeightAmplitude+1,
+	)
 }
 
 // generates basic blocks ( sand, water, etc. )
 func (generator *OverworldGenerator) genBase(x, y uint64) types.Block {
-	baseHeight := applyCircularMask(generator.metadata.Size, float64(x), float64(y),
-		height(generator.basePerlin, x, y, config.PerlinNoiseScaleFactor),
-	)
+	return generator.genGround(generator.biomeAt(x, y).info(), x, y)
+}
 
-	switch {
-	case baseHeight <= WaterHeight: // Water
-		return types.NewWaterBlock()
-	case baseHeight <= SandHeight: // Sand
-		return types.NewSandBlock()
-	default: // Grass
-		return types.NewGrassBlock()
+// generates the base block of the biome
+func (generator *OverworldGenerator) genGround(biome *biomeInfo, x, y uint64) types.Block {
+	if biome.poolHeight > 0 && height(generator.secondaryPerlin, x, y, generator.preset.NoiseScale) <= biome.poolHeight {
+		return types.NewBlock(types.WaterBlock)
 	}
+	return biome.ground()
 }
 
 // Checks if 8 neighbors of the block are of the same type
@@ -105,94 +131,71 @@ func (generator *OverworldGenerator) checkNeighbors(desiredType types.BlockType,
 	return true
 }
 
-// generates block features, depending on previous block
-func (generator *OverworldGenerator) genFeatures(previous types.Block, x, y uint64) types.Block {
+// generates block features, depending on previous block and the biome
+func (generator *OverworldGenerator) genFeatures(previous types.Block, biome *biomeInfo, x, y uint64) types.Block {
 	features := makeFeatures(generator.secondaryPerlin, x*16, y*16)
 
 	// do not apply circular mask, while generating block features
-	secondaryHeight := height(generator.secondaryPerlin, x, y, config.PerlinNoiseScaleFactor)
+	secondaryHeight := height(generator.secondaryPerlin, x, y, generator.preset.NoiseScale)
+	offset := generator.preset.FoliageHeightOffset
 
 	switch previous.Type() {
+	case types.WaterBlock:
+		return previous
 	case types.SandBlock:
-		// With 3% change, generate sand with flint or clay
-		if features.f1 <= SandWithParticlesChance {
+		// generate sand with flint or clay
+		if features.f1 <= biome.sandParticlesChance {
 			if features.f2 <= 0.5 {
-				return types.NewSandWithStonesBlock()
+				return types.NewBlock(types.SandWithStonesBlock)
 			} else {
-				return types.NewSandWithClayBlock()
+				return types.NewBlock(types.SandWithClayBlock)
 			}
 		}
-	case types.GrassBlock:
-		// generate features on grass, only if it is surrounded by grass on all sides
-		if !generator.checkNeighbors(types.GrassBlock, x, y) {
-			return previous
-		}
-
-		switch {
-		case secondaryHeight <= GrassHeight: // Empty grass
-			return previous
-		case secondaryHeight <= FoliageHeight: // Foliage
-			switch {
-			case features.f1 <= BerryBushChance:
-				// Berry bush can be generated with 0-2 berries randomly
-				return types.NewBerryBushBlock(int(features.f2) * 2)
-			case features.f1 <= MushroomChance:
-				if features.f2 <= 0.5 {
-					return types.NewRedMushroomBlock()
-				} else {
-					return types.NewWhiteMushroomBlock()
-				}
-			case features.f1 <= FlowerChance:
-				return types.NewFlowersBlock()
-			}
-
-			return types.NewShortGrassBlock()
-		default: // Tree
-			return types.NewPineTreeBlock()
-		}
+		return previous
 	}
 
-	// pass the base block forward, without any modifications
-	return previous
-}
+	// generate features on the ground, only if it is surrounded by the same ground on all sides
+	if !generator.checkNeighbors(previous.Type(), x, y) {
+		return previous
+	}
 
-func (generator *OverworldGenerator) generateStructures(chunk types.Chunk) {
-	chunkCoords := chunk.BlockCoords()
-	features := makeFeatures(generator.secondaryPerlin, chunkCoords.X, chunkCoords.Y)
-
-	if features.f1 < CaveEntranceChance {
-		// use a bunch of hardcoded possible coordinates for cave entrance
-		// it is just easier than trying to make reproducible RNG
-		possibleCoordinates := []types.Vec2u{
-			{X: 8, Y: 4},
-			{X: 4, Y: 12},
-			{X: 12, Y: 12},
+	switch {
+	case secondaryHeight <= biome.emptyHeight+offset: // Empty ground
+		return previous
+	case secondaryHeight <= biome.foliageHeight+offset: // Foliage
+		// chances are stacked on top of each other, so they don't overlap
+		chances := generator.preset.FoliageChances[biome.name]
+		chance := chances.BerryBush
+		if features.f1 <= chance {
+			// Berry bush can be generated with 0-2 berries randomly
+			return types.NewBerryBushBlock(int(features.f2) * 2)
 		}
-
-		// iterate over all possible coordinates, and pick the first valid pair
-		var chosenCoordinates types.Vec2u
-		valid := false
-		for _, coords := range possibleCoordinates {
-			// valid positions for cave entrance are those that are surrounded by grass blocks on all sides
-			if generator.checkNeighbors(types.GrassBlock, chunkCoords.X+coords.X, chunkCoords.Y+coords.Y) {
-				chosenCoordinates = coords
-				valid = true
-				break
+		chance += chances.Mushroom
+		if features.f1 <= chance {
+			if features.f2 <= 0.5 {
+				return types.NewBlock(types.RedMushroomBlock)
+			} else {
+				return types.NewBlock(types.WhiteMushroomBlock)
 			}
 		}
-		if !valid {
-			return
+		chance += chances.Flower
+		if features.f1 <= chance {
+			return types.NewBlock(types.FlowersBlock)
+		}
+		chance += chances.TallGrass
+		if features.f1 <= chance {
+			return types.NewBlock(types.TallGrassBlock)
 		}
 
-		// kinda slow but reproducible with the same seed, which is the most important
-		rng := rand.New(rand.NewSource(features.i1))
-		id, err := uuid.NewRandomFromReader(rng)
-		if err != nil {
-			// this should really never happen
-			log.Panicf("failed to generate UUID for cave: %v", err)
+		if biome.foliage != nil {
+			return biome.foliage()
+		}
+		return previous
+	default: // Tree
+		if biome.tree != nil {
+			return biome.tree()
 		}
-		log.Printf("cave at %v, %v: %v", chunkCoords.X+chosenCoordinates.X, chunkCoords.Y+chosenCoordinates.Y, id)
-		chunk.SetBlock(uint(chosenCoordinates.X), uint(chosenCoordinates.Y), types.NewCaveEntranceBlock(id))
+		return previous
 	}
 }
 
@@ -204,9 +207,11 @@ func (generator *OverworldGenerator) generate(chunk types.Chunk) {
 			bx := chunkCoordinates.X*16 + uint64(x)
 			by := chunkCoordinates.Y*16 + uint64(y)
 
+			biome := generator.biomeAt(bx, by).info()
+
 			var generated types.Block
-			generated = generator.genBase(bx, by)
-			generated = generator.genFeatures(generated, bx, by)
+			generated = generator.genGround(biome, bx, by)
+			generated = generator.genFeatures(generated, biome, bx, by)
 
 			chunk.SetBlock(x, y, generated)
 		}
@@ -219,7 +224,7 @@ func (generator *OverworldGenerator) generate(chunk types.Chunk) {
 func (generator *OverworldGenerator) generateDummy(chunk types.Chunk) {
 	for x := uint(0); x < 16; x++ {
 		for y := uint(0); y < 16; y++ {
-			chunk.SetBlock(x, y, types.NewWaterBlock())
+			chunk.SetBlock(x, y, types.NewBlock(types.WaterBlock))
 		}
 	}
 }
@@ -0,0 +1,131 @@
+package worldgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/3elDU/bamboo/assets"
+	"github.com/3elDU/bamboo/config"
+	"github.com/3elDU/bamboo/types"
+)
+
+// Name of the built-in preset
+const DefaultPresetName = "default"
+
+// Returns the built-in preset. Worlds created before presets were introduced use it as well.
+// Its noise values are the ones the overworld was generated with back then, but biomes, rivers and structures
+// were added since, so the new chunks of these worlds don't line up with the saved ones (see CHANGELOG.md).
+// Changing these values changes the terrain of all worlds without a preset in the save.
+func DefaultPreset() types.WorldgenPreset {
+	return types.WorldgenPreset{
+		Name: DefaultPresetName,
+
+		NoiseScale:      config.PerlinNoiseScaleFactor,
+		NoiseAlpha:      2,
+		NoiseBeta:       2,
+		NoiseOctaves:    16,
+		HeightAmplitude: 1,
+
+		WaterHeight: 1.0,
+		SandHeight:  1.1,
+
+		MaskRadius:  2.5,
+		MaskFalloff: 1.5,
+
+		BiomeScale:        config.PerlinNoiseScaleFactor * 3,
+		FoliageChances:    defaultFoliageChances(),
+		RiverSourceHeight: 1.2,
+		RiverChance:       0.8,
+	}
+}
+
+func defaultFoliageChances() map[string]types.FoliageChances {
+	return map[string]types.FoliageChances{
+		"meadow":       {BerryBush: 0.005, Mushroom: 0.01, Flower: 0.045, TallGrass: 0.02},
+		"pine forest":  {BerryBush: 0.015, Mushroom: 0.03, Flower: 0.005, TallGrass: 0.05},
+		"snowy tundra": {BerryBush: 0.01},
+		"swamp":        {Mushroom: 0.05, TallGrass: 0.4},
+	}
+}
+
+// Returns the preset stored in the save, or the default one for older worlds
+func PresetFor(metadata types.Save) types.WorldgenPreset {
+	if metadata.Preset == nil {
+		return DefaultPreset()
+	}
+	preset := *metadata.Preset
+	if preset.FoliageChances == nil {
+		preset.FoliageChances = defaultFoliageChances()
+	}
+	return preset
+}
+
+// Reads the preset from a JSON file.
+// Values missing from the file are taken from the default preset,
+// and the name defaults to the file name without the extension.
+// Biomes listed in foliage_chances replace all of their default chances.
+func LoadPreset(file assets.DefinitionFile) (types.WorldgenPreset, error) {
+	preset := DefaultPreset()
+	preset.Name = strings.TrimSuffix(path.Base(file.Path), path.Ext(file.Path))
+
+	if err := json.Unmarshal(file.Data, &preset); err != nil {
+		return preset, fmt.Errorf("failed to decode preset %v - %v", file.Path, err)
+	}
+	if err := validatePreset(preset); err != nil {
+		return preset, fmt.Errorf("invalid preset %v - %v", file.Path, err)
+	}
+	return preset, nil
+}
+
+func validatePreset(preset types.WorldgenPreset) error {
+	switch {
+	case preset.Name == "":
+		return fmt.Errorf("preset has no name")
+	case preset.NoiseScale <= 0 || preset.BiomeScale <= 0:
+		return fmt.Errorf("noise scale must be positive")
+	case preset.NoiseOctaves <= 0:
+		return fmt.Errorf("noise must have at least one octave")
+	case preset.MaskRadius <= 0 || preset.MaskFalloff <= 0:
+		return fmt.Errorf("mask radius and falloff must be positive")
+	case preset.SandHeight < preset.WaterHeight:
+		return fmt.Errorf("sand height is below water height")
+	}
+	for name := range preset.StructureChances {
+		if structureIndex(name) == -1 {
+			return fmt.Errorf("unknown structure %q", name)
+		}
+	}
+	for name, chances := range preset.FoliageChances {
+		if _, exists := biomeByName(name); !exists {
+			return fmt.Errorf("unknown biome %q", name)
+		}
+		if chances.BerryBush < 0 || chances.Mushroom < 0 || chances.Flower < 0 || chances.TallGrass < 0 {
+			return fmt.Errorf("foliage chances of %q can't be negative", name)
+		}
+		if chances.BerryBush+chances.Mushroom+chances.Flower+chances.TallGrass > 1 {
+			return fmt.Errorf("foliage chances of %q add up to more than 1", name)
+		}
+	}
+	return nil
+}
+
+// Returns the default preset, followed by presets from assets/definitions/presets, sorted by file name.
+// The presets are embedded into the game, so they don't depend on the working directory.
+func LoadPresets() []types.WorldgenPreset {
+	presets := []types.WorldgenPreset{DefaultPreset()}
+
+	for _, file := range assets.Definitions("presets") {
+		preset, err := LoadPreset(file)
+		if err != nil {
+			log.Panicf("LoadPresets - %v", err)
+		}
+		if preset.Name == DefaultPresetName {
+			log.Panicf("LoadPresets - %v: the default preset can't be overridden", file.Path)
+		}
+		presets = append(presets, preset)
+	}
+	return presets
+}
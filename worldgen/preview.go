@@ -0,0 +1,88 @@
+package worldgen
+
+import (
+	"context"
+
+	"github.com/3elDU/bamboo/types"
+	"github.com/3elDU/bamboo/util"
+)
+
+// Low resolution map of the overworld, used to preview the seed before the world is created
+type Preview struct {
+	// Size of the preview in samples
+	Width, Height int
+	// Base block of each sample, indexed as [x][y]
+	Blocks [][]types.BlockType
+	// How many blocks one sample covers
+	Step uint64
+
+	// Generator of the previewed world, so that chunks can be generated to find the spawn point
+	implementation *OverworldGenerator
+}
+
+// Samples base blocks of the overworld, so that the preview has at most `resolution` samples on each side.
+// Only genBase is used for each sample, so this is much faster than generating the chunks,
+// but it takes a while for big worlds anyway, so better run it in the background.
+// Returns the context error, if the context is cancelled before the preview is finished.
+// Tracing the rivers of the previewed world stops as well then.
+func NewPreview(ctx context.Context, metadata types.Save, resolution int) (*Preview, error) {
+	implementation := newOverworldGenerator(ctx, metadata)
+
+	size := metadata.Size
+	step := (util.Max(size.X, size.Y) + uint64(resolution) - 1) / uint64(resolution)
+	preview := &Preview{
+		Width:          int(size.X / step),
+		Height:         int(size.Y / step),
+		Step:           step,
+		implementation: implementation,
+	}
+
+	preview.Blocks = make([][]types.BlockType, preview.Width)
+	for x := range preview.Blocks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		preview.Blocks[x] = make([]types.BlockType, preview.Height)
+		for y := range preview.Blocks[x] {
+			// sample the middle of the area
+			bx, by := uint64(x)*step+step/2, uint64(y)*step+step/2
+			preview.Blocks[x][y] = implementation.genBase(bx, by).Type()
+		}
+	}
+	return preview, nil
+}
+
+// BlockTypeAt generates the chunk with the block, and returns type of the block.
+// Generated blocks aren't put into a chunk of a world, so they never schedule ticks,
+// and this is safe to call from any goroutine.
+func (preview *Preview) BlockTypeAt(x, y uint64) types.BlockType {
+	c := &blockTypeChunk{x: x / 16, y: y / 16}
+	preview.implementation.generate(c)
+	return c.blockTypes[x%16][y%16]
+}
+
+// Only keeps types of the blocks, that are set by the generator
+type blockTypeChunk struct {
+	x, y       uint64
+	blockTypes [16][16]types.BlockType
+}
+
+func (c *blockTypeChunk) At(x, y uint) types.Block {
+	return types.NewBlock(c.blockTypes[x][y])
+}
+func (c *blockTypeChunk) BlockCoords() types.Vec2u {
+	return types.Vec2u{X: c.x * 16, Y: c.y * 16}
+}
+func (c *blockTypeChunk) Coords() types.Vec2u {
+	return types.Vec2u{X: c.x, Y: c.y}
+}
+func (c *blockTypeChunk) SetBlock(x, y uint, block types.Block) {
+	c.blockTypes[x][y] = block.Type()
+}
+
+func (c *blockTypeChunk) Save(types.Save)                  {}
+func (c *blockTypeChunk) Update(types.World)               {}
+func (c *blockTypeChunk) ScheduleTick(_, _ uint, _ uint64) {}
+func (c *blockTypeChunk) TriggerRedraw(bool)               {}
+func (c *blockTypeChunk) MarkAsModified()                  {}
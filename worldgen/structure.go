@@ -0,0 +1,268 @@
+package worldgen
+
+import (
+	"hash/fnv"
+	"log"
+	"math/rand"
+
+	"github.com/3elDU/bamboo/types"
+)
+
+// Structure describes a group of blocks, that is placed into the world as a whole, like ruins or a cave entrance.
+//
+// The world is split into square regions, and each region gets one attempt at spawning the structure.
+// The structure always fits inside its region, but it may span several chunks.
+// Each chunk places the part of the structure, that lies inside of it,
+// so chunks can be generated in any order.
+type Structure struct {
+	Name string
+	// Footprint of the structure, in blocks
+	Size types.Vec2u
+	// Size of a region in blocks. Must be bigger than the footprint
+	Spacing uint64
+	// %Chance of spawning the structure in a region
+	Chance float64
+
+	// Spawn rules.
+	// Every block in the footprint must be in one of the biomes (any biome, if empty),
+	// and every block in the footprint and around it must have one of the ground types.
+	Biomes []Biome
+	Ground []types.BlockType
+
+	// Places blocks of the structure. Must only use the rng for randomness,
+	// as it is called once for every chunk that the structure spans, and must produce the same result every time
+	Place func(w *StructureWriter, rng *rand.Rand)
+}
+
+// How many random positions in a region are tried, before giving up on spawning the structure there
+const StructureAttempts = 8
+
+var structures []*Structure
+
+// Adds the structure to the overworld generator.
+// Structures registered earlier take precedence, if they overlap.
+func RegisterStructure(structure *Structure) {
+	if structure.Size.X >= structure.Spacing || structure.Size.Y >= structure.Spacing {
+		log.Panicf("structure %v doesn't fit into its region (size %v, spacing %v)", structure.Name, structure.Size, structure.Spacing)
+	}
+	if structureIndex(structure.Name) != -1 {
+		log.Panicf("structure %v is already registered", structure.Name)
+	}
+	structures = append(structures, structure)
+}
+
+// StructureWriter places blocks of the structure into the chunk, that is being generated.
+// Coordinates are relative to the top-left corner of the structure.
+// Blocks outside of the chunk are skipped, they are placed when the neighboring chunk is generated.
+type StructureWriter struct {
+	generator *OverworldGenerator
+	chunk     types.Chunk
+	origin    types.Vec2u
+	size      types.Vec2u
+}
+
+func (w *StructureWriter) Size() types.Vec2u {
+	return w.size
+}
+
+// World coordinates of the block of the structure
+func (w *StructureWriter) WorldCoords(x, y uint64) types.Vec2u {
+	return types.Vec2u{X: w.origin.X + x, Y: w.origin.Y + y}
+}
+
+func (w *StructureWriter) Set(x, y uint64, block types.Block) {
+	chunkCoords := w.chunk.BlockCoords()
+	bx, by := w.origin.X+x, w.origin.Y+y
+	if bx < chunkCoords.X || by < chunkCoords.Y || bx >= chunkCoords.X+16 || by >= chunkCoords.Y+16 {
+		return
+	}
+	w.chunk.SetBlock(uint(bx-chunkCoords.X), uint(by-chunkCoords.Y), block)
+}
+
+// Replaces the block with plain ground, removing any foliage or trees
+func (w *StructureWriter) Clear(x, y uint64) {
+	w.Set(x, y, w.generator.genBase(w.origin.X+x, w.origin.Y+y))
+}
+
+// Where a structure was placed in a region
+type structurePlacement struct {
+	origin types.Vec2u
+	// seed for the rng, that is passed to Structure.Place
+	seed int64
+	// false if the structure didn't spawn in this region
+	spawned bool
+}
+
+type placementKey struct {
+	structure int
+	region    types.Vec2u
+}
+
+// Decides, whether the structure spawns in the region, and where.
+// Results are cached, as neighboring chunks ask about the same regions.
+func (generator *OverworldGenerator) structureIn(index int, region types.Vec2u) structurePlacement {
+	key := placementKey{structure: index, region: region}
+
+	generator.structuresMutex.Lock()
+	placement, cached := generator.placements[key]
+	generator.structuresMutex.Unlock()
+	if cached {
+		return placement
+	}
+
+	// computed without holding the mutex, because overlap checks look at other regions.
+	// Another worker may compute the same placement at the same time, but the result is the same
+	placement = generator.placeStructure(index, region)
+
+	generator.structuresMutex.Lock()
+	generator.placements[key] = placement
+	generator.structuresMutex.Unlock()
+	return placement
+}
+
+func (generator *OverworldGenerator) placeStructure(index int, region types.Vec2u) structurePlacement {
+	structure := structures[index]
+
+	// structures get separate random sequences, derived from their names,
+	// so registering a new structure doesn't move the old ones
+	name := fnv.New64a()
+	name.Write([]byte(structure.Name))
+	rng := rand.New(rand.NewSource(
+		generator.structureSeed ^ int64(name.Sum64()) ^ int64(region.X)*73856093 ^ int64(region.Y)*19349663,
+	))
+
+	// all random numbers are taken up front, so the result doesn't depend on which checks pass
+	chance := rng.Float64()
+	var candidates [StructureAttempts]types.Vec2u
+	for i := range candidates {
+		candidates[i] = types.Vec2u{
+			X: region.X*structure.Spacing + rng.Uint64()%(structure.Spacing-structure.Size.X),
+			Y: region.Y*structure.Spacing + rng.Uint64()%(structure.Spacing-structure.Size.Y),
+		}
+	}
+	placement := structurePlacement{seed: rng.Int63()}
+
+	if chance > generator.structureChance(structure) {
+		return placement
+	}
+
+	// the first position, where the structure fits, is taken
+	size := generator.metadata.Size
+	for _, origin := range candidates {
+		// structure must be inside the world, with space for the border around it
+		if origin.X == 0 || origin.Y == 0 || origin.X+structure.Size.X >= size.X || origin.Y+structure.Size.Y >= size.Y {
+			continue
+		}
+		if !generator.structureFits(structure, origin) || generator.structureOverlaps(index, origin, structure.Size) {
+			continue
+		}
+
+		placement.origin = origin
+		placement.spawned = true
+		break
+	}
+	return placement
+}
+
+func (generator *OverworldGenerator) structureFits(structure *Structure, origin types.Vec2u) bool {
+	for x := origin.X - 1; x <= origin.X+structure.Size.X; x++ {
+		for y := origin.Y - 1; y <= origin.Y+structure.Size.Y; y++ {
+			biome := generator.biomeAt(x, y)
+
+			insideFootprint := x >= origin.X && y >= origin.Y && x < origin.X+structure.Size.X && y < origin.Y+structure.Size.Y
+			if insideFootprint && len(structure.Biomes) > 0 && !containsBiome(structure.Biomes, biome) {
+				return false
+			}
+
+			ground := generator.genGround(biome.info(), x, y).Type()
+			if !containsBlockType(structure.Ground, ground) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Checks whether the area overlaps with any structure, that was registered before this one
+func (generator *OverworldGenerator) structureOverlaps(index int, origin, size types.Vec2u) bool {
+	for other := 0; other < index; other++ {
+		spacing := structures[other].Spacing
+		// regions of the other structure, that the area touches
+		for rx := (origin.X - 1) / spacing; rx <= (origin.X+size.X)/spacing; rx++ {
+			for ry := (origin.Y - 1) / spacing; ry <= (origin.Y+size.Y)/spacing; ry++ {
+				placement := generator.structureIn(other, types.Vec2u{X: rx, Y: ry})
+				// keep at least one block between structures
+				withBorder := types.Vec2u{X: origin.X - 1, Y: origin.Y - 1}
+				sizeWithBorder := types.Vec2u{X: size.X + 2, Y: size.Y + 2}
+				if placement.spawned && rectanglesOverlap(withBorder, sizeWithBorder, placement.origin, structures[other].Size) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Places parts of all structures, that overlap the chunk
+func (generator *OverworldGenerator) generateStructures(chunk types.Chunk) {
+	chunkCoords := chunk.BlockCoords()
+
+	for index, structure := range structures {
+		for rx := chunkCoords.X / structure.Spacing; rx <= (chunkCoords.X+15)/structure.Spacing; rx++ {
+			for ry := chunkCoords.Y / structure.Spacing; ry <= (chunkCoords.Y+15)/structure.Spacing; ry++ {
+				placement := generator.structureIn(index, types.Vec2u{X: rx, Y: ry})
+				if !placement.spawned || !rectanglesOverlap(placement.origin, structure.Size, chunkCoords, types.Vec2u{X: 16, Y: 16}) {
+					continue
+				}
+
+				structure.Place(&StructureWriter{
+					generator: generator,
+					chunk:     chunk,
+					origin:    placement.origin,
+					size:      structure.Size,
+				}, rand.New(rand.NewSource(placement.seed)))
+			}
+		}
+	}
+}
+
+// Chance from the preset, if it has one for this structure
+func (generator *OverworldGenerator) structureChance(structure *Structure) float64 {
+	if chance, exists := generator.preset.StructureChances[structure.Name]; exists {
+		return chance
+	}
+	return structure.Chance
+}
+
+// Returns the index of the registered structure, or -1 if there is no structure with this name
+func structureIndex(name string) int {
+	for i, structure := range structures {
+		if structure.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func rectanglesOverlap(origin1, size1, origin2, size2 types.Vec2u) bool {
+	return origin1.X < origin2.X+size2.X && origin2.X < origin1.X+size1.X &&
+		origin1.Y < origin2.Y+size2.Y && origin2.Y < origin1.Y+size1.Y
+}
+
+func containsBiome(biomes []Biome, biome Biome) bool {
+	for _, b := range biomes {
+		if b == biome {
+			return true
+		}
+	}
+	return false
+}
+
+func containsBlockType(blockTypes []types.BlockType, blockType types.BlockType) bool {
+	for _, t := range blockTypes {
+		if t == blockType {
+			return true
+		}
+	}
+	return false
+}
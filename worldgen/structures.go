@@ -0,0 +1,138 @@
+package worldgen
+
+import (
+	"log"
+	"math"
+	"math/rand"
+
+	"github.com/3elDU/bamboo/types"
+	"github.com/google/uuid"
+)
+
+func init() {
+	RegisterStructure(&Structure{
+		Name:    "cave entrance",
+		Size:    types.Vec2u{X: 1, Y: 1},
+		Spacing: 16,
+		Chance:  CaveEntranceChance,
+		Ground:  []types.BlockType{types.GrassBlock, types.SnowBlock},
+		Place:   placeCaveEntrance,
+	})
+	RegisterStructure(&Structure{
+		Name:    "ruins",
+		Size:    types.Vec2u{X: 7, Y: 7},
+		Spacing: 96,
+		Chance:  RuinsChance,
+		Biomes:  []Biome{MeadowBiome, PineForestBiome, SnowyTundraBiome, DesertBiome},
+		Ground:  []types.BlockType{types.GrassBlock, types.SnowBlock, types.SandBlock},
+		Place:   placeRuins,
+	})
+	RegisterStructure(&Structure{
+		Name:    "abandoned camp",
+		Size:    types.Vec2u{X: 5, Y: 5},
+		Spacing: 96,
+		Chance:  CampChance,
+		Biomes:  []Biome{MeadowBiome, PineForestBiome},
+		Ground:  []types.BlockType{types.GrassBlock},
+		Place:   placeCamp,
+	})
+	RegisterStructure(&Structure{
+		Name:    "stone circle",
+		Size:    types.Vec2u{X: 9, Y: 9},
+		Spacing: 128,
+		Chance:  StoneCircleChance,
+		Biomes:  []Biome{MeadowBiome, SnowyTundraBiome, SwampBiome},
+		Ground:  []types.BlockType{types.GrassBlock, types.SnowBlock},
+		Place:   placeStoneCircle,
+	})
+}
+
+func placeCaveEntrance(w *StructureWriter, rng *rand.Rand) {
+	// kinda slow but reproducible with the same seed, which is the most important
+	id, err := uuid.NewRandomFromReader(rng)
+	if err != nil {
+		// this should really never happen
+		log.Panicf("failed to generate UUID for cave: %v", err)
+	}
+	coords := w.WorldCoords(0, 0)
+	log.Printf("cave at %v, %v: %v", coords.X, coords.Y, id)
+	w.Set(0, 0, types.NewCaveEntranceBlock(id))
+}
+
+// Square room with crumbling stone walls, and a doorway on one side
+func placeRuins(w *StructureWriter, rng *rand.Rand) {
+	size := w.Size()
+	doorway := rng.Intn(4)
+
+	for x := uint64(0); x < size.X; x++ {
+		for y := uint64(0); y < size.Y; y++ {
+			// decide randomly for every block, so the rng is used the same way in every chunk
+			intact := rng.Float64() < 0.7
+			rubble := rng.Float64() < 0.08
+
+			wall := x == 0 || y == 0 || x == size.X-1 || y == size.Y-1
+			isDoorway := (doorway == 0 && y == 0 && x == size.X/2) ||
+				(doorway == 1 && y == size.Y-1 && x == size.X/2) ||
+				(doorway == 2 && x == 0 && y == size.Y/2) ||
+				(doorway == 3 && x == size.X-1 && y == size.Y/2)
+
+			switch {
+			case wall && !isDoorway && intact:
+				w.Set(x, y, types.NewBlock(types.StoneBlock))
+			case !wall && rubble:
+				w.Set(x, y, types.NewBlock(types.StoneBlock))
+			default:
+				w.Clear(x, y)
+			}
+		}
+	}
+}
+
+// Burnt out campfire in a clearing, with a few bushes and saplings left by whoever lived here
+func placeCamp(w *StructureWriter, rng *rand.Rand) {
+	size := w.Size()
+	for x := uint64(0); x < size.X; x++ {
+		for y := uint64(0); y < size.Y; y++ {
+			w.Clear(x, y)
+		}
+	}
+
+	w.Set(size.X/2, size.Y/2, types.NewBlock(types.CampfireBlock))
+
+	corners := []types.Vec2u{{X: 0, Y: 0}, {X: size.X - 1, Y: 0}, {X: 0, Y: size.Y - 1}, {X: size.X - 1, Y: size.Y - 1}}
+	rng.Shuffle(len(corners), func(i, j int) { corners[i], corners[j] = corners[j], corners[i] })
+	// berry bushes are picked clean
+	w.Set(corners[0].X, corners[0].Y, types.NewBerryBushBlock(0))
+	w.Set(corners[1].X, corners[1].Y, types.NewBlock(types.PineSaplingBlock))
+	if rng.Float64() < 0.5 {
+		w.Set(corners[2].X, corners[2].Y, types.NewBlock(types.PineSaplingBlock))
+	}
+}
+
+// Ring of stones, with flowers growing in the middle
+func placeStoneCircle(w *StructureWriter, rng *rand.Rand) {
+	size := w.Size()
+	for x := uint64(0); x < size.X; x++ {
+		for y := uint64(0); y < size.Y; y++ {
+			w.Clear(x, y)
+		}
+	}
+
+	const stones = 8
+	center := float64(size.X-1) / 2
+	radius := center - 0.5
+	for i := 0; i < stones; i++ {
+		// some stones have fallen over and sunk into the ground
+		fallen := rng.Float64() < 0.15
+		if fallen {
+			continue
+		}
+
+		angle := float64(i) / stones * 2 * math.Pi
+		x := uint64(math.Round(center + math.Cos(angle)*radius))
+		y := uint64(math.Round(center + math.Sin(angle)*radius))
+		w.Set(x, y, types.NewBlock(types.StoneBlock))
+	}
+
+	w.Set(size.X/2, size.Y/2, types.NewBlock(types.FlowersBlock))
+}
@@ -11,8 +11,8 @@ func NewWorldgenForWorld(metadata types.Save) types.WorldGenerator {
 	switch metadata.WorldType {
 	case world_type.Overworld:
 		return NewOverworldGenerator(metadata)
-	case world_type.Cave:
-		return NewCaveGenerator(metadata.Seed)
+	case world_type.Cave, world_type.Cave2, world_type.Cave3:
+		return NewCaveGenerator(metadata.Seed, metadata.WorldType)
 	}
 
 	log.Panicf("no world generator associated with world type %v", metadata)